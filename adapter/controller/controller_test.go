@@ -0,0 +1,104 @@
+package controller_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	_ "github.com/8treenet/dump/adapter/controller"
+	"github.com/8treenet/dump/adapter/repository"
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra/apptest"
+	_ "github.com/8treenet/dump/server/migrations"
+	"github.com/8treenet/freedom"
+)
+
+var harness *apptest.Harness
+
+func TestMain(m *testing.M) {
+	var err error
+	harness, err = apptest.New(apptest.Options{
+		MigrationsDir: "../../server/migrations",
+		FixturesDir:   "../../server/fixtures",
+	})
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	harness.Close()
+	os.Exit(code)
+}
+
+// TestGoods reads a fixture row through the controller.
+func TestGoods(t *testing.T) {
+	resp, err := harness.Client.Get("/goods/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goods := &po.Goods{}
+	envelope, err := resp.Envelope(goods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Code != 0 || goods.Name != "苹果" || goods.Stock != 100 {
+		t.Fatalf("unexpected response %d %s", resp.StatusCode, resp.Body)
+	}
+	if resp.Header.Get("ETag") == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	if resp, err = harness.Client.Get("/goods/404"); err != nil {
+		t.Fatal(err)
+	}
+	if envelope, err = resp.Envelope(nil); err != nil || envelope.Code != 404 {
+		t.Fatalf("expected code 404, got %s", resp.Body)
+	}
+}
+
+// TestRepository runs a repository with the worker of a request.
+func TestRepository(t *testing.T) {
+	err := harness.Call(func(worker freedom.Worker) {
+		repo := &repository.Goods{}
+		repo.Worker = worker
+		goods, e := repo.Goods(2)
+		if e != nil {
+			panic(e)
+		}
+		if goods.Name != "香蕉" {
+			panic("unexpected goods " + goods.Name)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestImportDryRun checks that a dry run sees the keys of its previous batches and writes nothing.
+func TestImportDryRun(t *testing.T) {
+	content := "name,price,stock,tag\n葡萄,5,10,水果\n葡萄,5,10,水果\n"
+	var summary *dto.ImportSummary
+	err := harness.Call(func(worker freedom.Worker) {
+		var service *domain.Import
+		apptest.Service(worker, &service)
+		var e error
+		summary, e = service.Goods(strings.NewReader(content), domain.ImportOptions{Format: "csv", DryRun: true, BatchSize: 1})
+		if e != nil {
+			panic(e)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Inserted != 1 || summary.Skipped != 1 {
+		t.Fatalf("expected 1 inserted and 1 skipped, got %+v", summary)
+	}
+	count := 0
+	if err := harness.DB.Model(&po.Goods{}).Where("name = ?", "葡萄").Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("the dry run wrote %d rows", count)
+	}
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/export"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindController("/admin/exports", &Export{})
+	})
+}
+
+// Export downloads orders, order details, deliveries and goods inventory for admin reporting.
+type Export struct {
+	Sev     *domain.Export
+	Worker  freedom.Worker
+	Request *infra.Request
+}
+
+// exportJob is the answer of an async export.
+type exportJob struct {
+	export.Job
+	URL string `json:"url"`
+}
+
+// GetBy handles the GET: /admin/exports/{dataset:string} route.
+// dataset is orders, order-details, deliveries or goods. Query parameters:
+// format csv or xlsx, columns a comma separated list of columns, async=true to export in the background,
+// the other parameters are the filters of the dataset, e.g. status, createdFrom, createdTo.
+func (c *Export) GetBy(dataset string) freedom.Result {
+	ctx := c.Worker.IrisContext()
+	format := ctx.URLParamDefault("format", export.FormatCSV)
+	if _, ok := export.ContentTypes[format]; !ok {
+		return &infra.JSONResponse{Code: 400, Error: fmt.Errorf("unknown format '%s', expected csv or xlsx", format)}
+	}
+	selected := []string{}
+	if columns := ctx.URLParam("columns"); columns != "" {
+		selected = strings.Split(columns, ",")
+	}
+	columns, e := c.Sev.Columns(dataset, selected)
+	if e != nil {
+		return &infra.JSONResponse{Code: 400, Error: e}
+	}
+	fileName := c.Sev.FileName(dataset, format)
+
+	if async, _ := ctx.URLParamBool("async"); async {
+		//后台任务在请求结束后读取, 不能使用请求的worker和数据库
+		rows, e := c.Sev.BackgroundRows(dataset, ctx.URLParams())
+		if e != nil {
+			return &infra.JSONResponse{Code: 400, Error: e}
+		}
+		job, e := export.Start(fileName, func(w io.Writer) (int, error) {
+			return export.Write(format, w, columns, rows)
+		})
+		if e != nil {
+			return &infra.JSONResponse{Error: e}
+		}
+		c.Worker.Logger().Infof("export %s started, job: %s", fileName, job.ID)
+		return &infra.JSONResponse{Object: exportJob{Job: job, URL: jobFileURL(job.ID)}}
+	}
+	rows, e := c.Sev.Rows(dataset, ctx.URLParams())
+	if e != nil {
+		return &infra.JSONResponse{Code: 400, Error: e}
+	}
+	return &infra.ExportResponse{Format: format, FileName: fileName, Columns: columns, Rows: rows}
+}
+
+// GetJobsBy handles the GET: /admin/exports/jobs/{id:string} route.
+func (c *Export) GetJobsBy(id string) freedom.Result {
+	job, ok := export.Lookup(id)
+	if !ok {
+		return &infra.JSONResponse{Code: 404, Error: fmt.Errorf("export job '%s' not found", id)}
+	}
+	return &infra.JSONResponse{Object: exportJob{Job: job, URL: jobFileURL(job.ID)}}
+}
+
+// GetJobsByFile handles the GET: /admin/exports/jobs/{id:string}/file route.
+func (c *Export) GetJobsByFile(id string) freedom.Result {
+	job, ok := export.Lookup(id)
+	if !ok {
+		return &infra.JSONResponse{Code: 404, Error: fmt.Errorf("export job '%s' not found", id)}
+	}
+	if job.Status != export.StatusDone {
+		return &infra.JSONResponse{Code: 409, Error: fmt.Errorf("export job '%s' is %s", id, job.Status)}
+	}
+	ext := job.FileName[strings.LastIndex(job.FileName, ".")+1:]
+	return &infra.FileResponse{Path: job.Path(), FileName: job.FileName, ContentType: export.ContentTypes[ext]}
+}
+
+func jobFileURL(id string) string {
+	return "/admin/exports/jobs/" + id + "/file"
+}
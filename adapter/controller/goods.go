@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindController("/goods", &Goods{})
+	})
+}
+
+// Goods serves the goods with conditional requests: GET answers 304 to a fresh copy,
+// PUT and PATCH answer 412 when If-Match or If-Unmodified-Since names a stale copy.
+type Goods struct {
+	Sev     *domain.Goods
+	Worker  freedom.Worker
+	Request *infra.Request
+}
+
+// Get handles the GET: /goods route, the goods are streamed as they are read.
+func (c *Goods) Get() freedom.Result {
+	rows, e := c.Sev.List()
+	if e != nil {
+		return &infra.JSONResponse{Error: e}
+	}
+	return &infra.StreamResponse{Rows: rows}
+}
+
+// GetBy handles the GET: /goods/{id:int} route.
+func (c *Goods) GetBy(id int) freedom.Result {
+	goods, e := c.Sev.Get(id)
+	if e != nil {
+		return notFoundResponse(e)
+	}
+	return &infra.JSONResponse{Object: goods, ETag: infra.VersionETag(goods.Version), LastModified: goods.Updated}
+}
+
+// PutBy handles the PUT: /goods/{id:int} route, the fields absent from the body are unchanged.
+func (c *Goods) PutBy(id int) freedom.Result {
+	var update dto.GoodsUpdate
+	if e := c.Request.ReadJSON(&update); e != nil {
+		return &infra.JSONResponse{Code: 400, Error: e}
+	}
+	goods, e := c.Sev.Update(id, update, c.Request.CheckPrecondition)
+	if e != nil {
+		return notFoundResponse(e)
+	}
+	return &infra.JSONResponse{Object: goods}
+}
+
+// PatchBy handles the PATCH: /goods/{id:int} route, as PutBy.
+func (c *Goods) PatchBy(id int) freedom.Result {
+	return c.PutBy(id)
+}
+
+// notFoundResponse is the response of an error of a single resource, code 404 when the row doesn't exist.
+func notFoundResponse(e error) freedom.Result {
+	if e == gorm.ErrRecordNotFound {
+		return &infra.JSONResponse{Code: 404, Error: e}
+	}
+	return &infra.JSONResponse{Error: e}
+}
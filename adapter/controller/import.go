@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/importer"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindController("/admin/imports", &Import{})
+	})
+}
+
+// Import bulk imports goods and users from CSV or JSON Lines.
+type Import struct {
+	Sev     *domain.Import
+	Worker  freedom.Worker
+	Request *infra.Request
+}
+
+// PostBy handles the POST: /admin/imports/{kind:string} route, kind is goods or users.
+// The file is the request body or the multipart field "file". Query parameters:
+// format csv or jsonl, by default taken from the file name; dryRun=true validates and rolls back;
+// mode insert skips the existing keys, upsert updates them; batchSize rows per transaction.
+func (c *Import) PostBy(kind string) freedom.Result {
+	ctx := c.Worker.IrisContext()
+	var body io.Reader = ctx.Request().Body
+	fileName := ""
+	if strings.HasPrefix(ctx.GetHeader("Content-Type"), "multipart/form-data") {
+		file, header, e := ctx.FormFile("file")
+		if e != nil {
+			return &infra.JSONResponse{Code: 400, Error: e}
+		}
+		defer file.Close()
+		body, fileName = file, header.Filename
+	}
+
+	opt := domain.ImportOptions{Format: ctx.URLParam("format")}
+	if opt.Format == "" {
+		opt.Format = importer.FormatCSV
+		if ext := strings.TrimPrefix(filepath.Ext(fileName), "."); ext == importer.FormatJSONL || ext == "json" {
+			opt.Format = importer.FormatJSONL
+		}
+	}
+	opt.DryRun, _ = ctx.URLParamBool("dryRun")
+	switch mode := ctx.URLParamDefault("mode", "insert"); mode {
+	case "insert":
+	case "upsert":
+		opt.Upsert = true
+	default:
+		return &infra.JSONResponse{Code: 400, Error: fmt.Errorf("unknown mode '%s', expected insert or upsert", mode)}
+	}
+	opt.BatchSize = ctx.URLParamIntDefault("batchSize", domain.ImportBatchSize)
+
+	var summary *dto.ImportSummary
+	var e error
+	switch kind {
+	case "goods":
+		summary, e = c.Sev.Goods(body, opt)
+	case "users":
+		summary, e = c.Sev.Users(body, opt)
+	default:
+		return &infra.JSONResponse{Code: 404, Error: fmt.Errorf("unknown import '%s', expected goods or users", kind)}
+	}
+	if e != nil && summary == nil {
+		return &infra.JSONResponse{Code: 400, Error: e}
+	}
+	if e != nil {
+		//导入中途失败, summary只包含已提交的批次
+		return &infra.JSONResponse{Code: 500, Error: e, Object: summary}
+	}
+	return &infra.JSONResponse{Object: summary}
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindController("/orders", &Order{})
+	})
+}
+
+// Order serves the orders with conditional requests, as Goods.
+type Order struct {
+	Sev     *domain.Order
+	Worker  freedom.Worker
+	Request *infra.Request
+}
+
+// GetBy handles the GET: /orders/{id:int} route.
+func (c *Order) GetBy(id int) freedom.Result {
+	order, e := c.Sev.Get(id)
+	if e != nil {
+		return notFoundResponse(e)
+	}
+	return &infra.JSONResponse{Object: order, ETag: infra.VersionETag(order.Version), LastModified: order.Updated}
+}
+
+// PutBy handles the PUT: /orders/{id:int} route, it updates the status of the order.
+func (c *Order) PutBy(id int) freedom.Result {
+	var update dto.OrderUpdate
+	if e := c.Request.ReadJSON(&update); e != nil {
+		return &infra.JSONResponse{Code: 400, Error: e}
+	}
+	order, e := c.Sev.Update(id, update, c.Request.CheckPrecondition)
+	if e != nil {
+		return notFoundResponse(e)
+	}
+	return &infra.JSONResponse{Object: order}
+}
+
+// PatchBy handles the PATCH: /orders/{id:int} route, as PutBy.
+func (c *Order) PatchBy(id int) freedom.Result {
+	return c.PutBy(id)
+}
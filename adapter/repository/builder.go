@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/masking"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+	"strings"
+)
+
+// GORMRepository .
+type GORMRepository interface {
+	db() *gorm.DB
+	GetWorker() freedom.Worker
+}
+
+// Builder .
+type Builder interface {
+	Execute(db *gorm.DB, object interface{}) error
+}
+
+// Pager .
+type Pager struct {
+	pageSize   int
+	page       int
+	totalPage  int
+	totalCount int
+	fields     []string
+	orders     []string
+}
+
+// NewPager .
+func NewPager() *Pager {
+	return &Pager{}
+}
+
+// NewPagerOf returns the pager of the paging of a request, see infra.Request.ReadPaging.
+func NewPagerOf(paging infra.Paging) *Pager {
+	pager := NewPager().SetPage(paging.Page, paging.PageSize)
+	for _, sort := range paging.Sorts {
+		pager.AddOrder(sort.Column, sort.Order)
+	}
+	return pager
+}
+
+// NewDescPager .
+func NewDescPager(column string, columns ...string) *Pager {
+	return newDefaultPager("desc", column, columns...)
+}
+
+// NewAscPager .
+func NewAscPager(column string, columns ...string) *Pager {
+	return newDefaultPager("asc", column, columns...)
+}
+
+// NewDescOrder .
+func newDefaultPager(sort, field string, args ...string) *Pager {
+	fields := []string{field}
+	fields = append(fields, args...)
+	orders := []string{}
+	for index := 0; index < len(fields); index++ {
+		orders = append(orders, sort)
+	}
+	return &Pager{
+		fields: fields,
+		orders: orders,
+	}
+}
+
+// Order . Columns are quoted for mysql, e.g. `id`, see OrderFor for the other databases.
+func (p *Pager) Order() interface{} {
+	return p.order(func(column string) string {
+		return "`" + column + "`"
+	})
+}
+
+// OrderFor . Columns are quoted by the dialect of the database, e.g. `id` for mysql and "id" for postgres and sqlite3.
+func (p *Pager) OrderFor(dialect gorm.Dialect) interface{} {
+	return p.order(dialect.Quote)
+}
+
+func (p *Pager) order(quote func(string) string) interface{} {
+	if len(p.fields) == 0 {
+		return nil
+	}
+	args := []string{}
+	for index := 0; index < len(p.fields); index++ {
+		args = append(args, fmt.Sprintf("%s %s", quote(p.fields[index]), p.orders[index]))
+	}
+
+	return strings.Join(args, ",")
+}
+
+// AddOrder appends a column to the ordering, sort is "asc" or "desc".
+func (p *Pager) AddOrder(column, sort string) *Pager {
+	p.fields = append(p.fields, column)
+	p.orders = append(p.orders, sort)
+	return p
+}
+
+// Orders returns the ordering columns and their sorts, "asc" or "desc".
+func (p *Pager) Orders() (columns, sorts []string) {
+	return p.fields, p.orders
+}
+
+// SetTotal sets the total count of the rows and the total pages, as Execute does after its query.
+func (p *Pager) SetTotal(count int) *Pager {
+	p.totalCount = count
+	if p.pageSize > 0 {
+		p.totalPage = (count + p.pageSize - 1) / p.pageSize
+	}
+	return p
+}
+
+// TotalPage .
+func (p *Pager) TotalPage() int {
+	return p.totalPage
+}
+
+// TotalCount .
+func (p *Pager) TotalCount() int {
+	return p.totalCount
+}
+
+// Page .
+func (p *Pager) Page() int {
+	return p.page
+}
+
+// PageSize .
+func (p *Pager) PageSize() int {
+	return p.pageSize
+}
+
+// SetPage .
+func (p *Pager) SetPage(page, pageSize int) *Pager {
+	p.page = page
+	p.pageSize = pageSize
+	return p
+}
+
+// Execute .
+func (p *Pager) Execute(db *gorm.DB, object interface{}) (e error) {
+	pageFind := false
+	orderValue := p.OrderFor(db.Dialect())
+	if orderValue != nil {
+		db = db.Order(orderValue)
+	} else {
+		db = db.Set("gorm:order_by_primary_key", "DESC")
+	}
+	if p.page != 0 && p.pageSize != 0 {
+		pageFind = true
+		db = db.Offset((p.page - 1) * p.pageSize).Limit(p.pageSize)
+	}
+
+	resultDB := db.Find(object)
+	if resultDB.Error != nil {
+		return resultDB.Error
+	}
+
+	if !pageFind {
+		return
+	}
+
+	var count int
+	e = resultDB.Offset(0).Limit(1).Count(&count).Error
+	if e == nil && count != 0 {
+		p.SetTotal(count)
+	}
+	return
+}
+
+func ormErrorLog(repo GORMRepository, model, method string, e error, expression ...interface{}) {
+	if e == nil || e == gorm.ErrRecordNotFound {
+		return
+	}
+	//日志中的对象按脱敏策略输出, 避免密码等敏感字段泄露。
+	masked := make([]interface{}, len(expression))
+	for index := range expression {
+		masked[index] = masking.Value(expression[index])
+	}
+	format := "Orm error, model: %s, method: %s, expression :%v, reason for error:%v"
+	//脱离请求的资源库没有worker, 例如Store和Detached的Export
+	if worker := repo.GetWorker(); worker != nil {
+		worker.Logger().Errorf(format, model, method, masked, e)
+		return
+	}
+	freedom.Logger().Errorf(format, model, method, masked, e)
+}
@@ -0,0 +1,360 @@
+// Package contract checks that the in-memory fakes and the gorm repositories behave the same,
+// every check runs against both of them, e.g. in a test with the apptest harness:
+//
+//	memory := repositorytest.NewMemoryStore()
+//	err := contract.Import(repositorytest.NewMemoryImport(memory), memory)
+//
+//	harness.Call(func(worker freedom.Worker) {
+//		repo := &repository.Import{}
+//		repo.Worker = worker
+//		err = contract.Import(repo, &repositorytest.GORMStore{DB: harness.DB})
+//	})
+//
+// The checks only touch the rows they create, tagged with a unique name, so they run on a non-empty database.
+package contract
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/8treenet/dump/adapter/repository"
+	"github.com/8treenet/dump/adapter/repository/repositorytest"
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/jinzhu/gorm"
+)
+
+// gorm资源库与内存实现都需满足domain的接口
+var (
+	_ domain.DefaultRepository = (*repository.Default)(nil)
+	_ domain.DefaultRepository = (*repositorytest.MemoryDefault)(nil)
+	_ domain.ExportRepository  = (*repository.Export)(nil)
+	_ domain.ExportRepository  = (*repositorytest.MemoryExport)(nil)
+	_ domain.ImportRepository  = (*repository.Import)(nil)
+	_ domain.ImportRepository  = (*repositorytest.MemoryImport)(nil)
+	_ domain.GoodsRepository   = (*repository.Goods)(nil)
+	_ domain.GoodsRepository   = (*repositorytest.MemoryGoods)(nil)
+	_ domain.OrderRepository   = (*repository.Order)(nil)
+	_ domain.OrderRepository   = (*repositorytest.MemoryOrder)(nil)
+	_ repositorytest.Store     = (*repositorytest.GORMStore)(nil)
+	_ repositorytest.Store     = (*repositorytest.MemoryStore)(nil)
+)
+
+var runs int64
+
+// unique returns a name no other check uses.
+func unique(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().UnixNano(), atomic.AddInt64(&runs, 1))
+}
+
+// Store checks finding, partial updates and paging.
+func Store(store repositorytest.Store) error {
+	tag := unique("contract-store")
+	if e := store.Find(&po.Goods{Tag: tag}); e != gorm.ErrRecordNotFound {
+		return fmt.Errorf("Find of a missing row: expected gorm.ErrRecordNotFound, got %v", e)
+	}
+
+	now := time.Now()
+	ids := map[int]bool{}
+	for index, price := range []int{30, 10, 50, 20, 40} {
+		goods := &po.Goods{Name: fmt.Sprintf("goods-%d", index), Price: price, Tag: tag, Created: now, Updated: now}
+		if e := store.Create(goods); e != nil {
+			return fmt.Errorf("Create: %v", e)
+		}
+		if goods.ID == 0 || ids[goods.ID] {
+			return fmt.Errorf("Create: expected a new primary key, got %d", goods.ID)
+		}
+		ids[goods.ID] = true
+	}
+
+	goods := &po.Goods{Name: "goods-2", Tag: tag}
+	if e := store.Find(goods); e != nil {
+		return fmt.Errorf("Find: %v", e)
+	}
+	if goods.Price != 50 {
+		return fmt.Errorf("Find: expected price 50, got %d", goods.Price)
+	}
+
+	//只有通过Set方法修改的列会被更新
+	goods.Stock = 999
+	goods.SetPrice(55)
+	if _, e := store.Save(goods); e != nil {
+		return fmt.Errorf("Save: %v", e)
+	}
+	saved := &po.Goods{ID: goods.ID}
+	if e := store.Find(saved); e != nil {
+		return fmt.Errorf("Find after Save: %v", e)
+	}
+	if saved.Price != 55 || saved.Stock != 0 {
+		return fmt.Errorf("Save: expected price 55 and stock 0, got price %d and stock %d", saved.Price, saved.Stock)
+	}
+
+	list := []*po.Goods{}
+	pager := repository.NewDescPager("price").SetPage(2, 2)
+	if e := store.FindList(po.Goods{Tag: tag}, &list, pager); e != nil {
+		return fmt.Errorf("FindList: %v", e)
+	}
+	if e := expectPrices(list, 30, 20); e != nil {
+		return fmt.Errorf("FindList, page 2 by price desc: %v", e)
+	}
+	if pager.TotalCount() != 5 || pager.TotalPage() != 3 {
+		return fmt.Errorf("FindList: expected 5 rows in 3 pages, got %d rows in %d pages", pager.TotalCount(), pager.TotalPage())
+	}
+
+	list = []*po.Goods{}
+	if e := store.FindList(po.Goods{Tag: tag}, &list, repository.NewAscPager("price")); e != nil {
+		return fmt.Errorf("FindList: %v", e)
+	}
+	if e := expectPrices(list, 10, 20, 30, 40, 55); e != nil {
+		return fmt.Errorf("FindList by price asc: %v", e)
+	}
+
+	list = []*po.Goods{}
+	if e := store.FindListByWhere("tag = ? AND price >= ?", []interface{}{tag, 30}, &list); e != nil {
+		return fmt.Errorf("FindListByWhere: %v", e)
+	}
+	if len(list) != 3 {
+		return fmt.Errorf("FindListByWhere: expected 3 rows, got %d", len(list))
+	}
+	return nil
+}
+
+// Import checks the transactions and partial updates of repo, store reads what repo committed.
+func Import(repo domain.ImportRepository, store repositorytest.Store) error {
+	now := time.Now()
+	committed, rolledBack := unique("contract-commit"), unique("contract-rollback")
+	for _, item := range []struct {
+		name  string
+		write func() error
+	}{
+		{committed, repo.Commit},
+		{rolledBack, repo.Rollback},
+	} {
+		if e := repo.Begin(); e != nil {
+			return fmt.Errorf("Begin: %v", e)
+		}
+		if e := repo.CreateGoods(&po.Goods{Name: item.name, Price: 10, Stock: 1, Created: now, Updated: now}); e != nil {
+			repo.Rollback()
+			return fmt.Errorf("CreateGoods: %v", e)
+		}
+		if e := item.write(); e != nil {
+			return fmt.Errorf("end of the transaction of %s: %v", item.name, e)
+		}
+	}
+
+	existing, e := repo.GoodsByNames([]string{committed, rolledBack})
+	if e != nil {
+		return fmt.Errorf("GoodsByNames: %v", e)
+	}
+	if len(existing) != 1 || existing[committed] == nil {
+		return fmt.Errorf("GoodsByNames: expected only the committed goods, got %d goods", len(existing))
+	}
+
+	goods := existing[committed]
+	goods.Stock = 999
+	goods.SetPrice(20)
+	if e := repo.Begin(); e != nil {
+		return fmt.Errorf("Begin: %v", e)
+	}
+	if e := repo.SaveGoods(goods); e != nil {
+		repo.Rollback()
+		return fmt.Errorf("SaveGoods: %v", e)
+	}
+	if e := repo.Commit(); e != nil {
+		return fmt.Errorf("Commit: %v", e)
+	}
+	saved := &po.Goods{ID: goods.ID}
+	if e := store.Find(saved); e != nil {
+		return fmt.Errorf("Find after SaveGoods: %v", e)
+	}
+	if saved.Price != 20 || saved.Stock != 1 {
+		return fmt.Errorf("SaveGoods: expected price 20 and stock 1, got price %d and stock %d", saved.Price, saved.Stock)
+	}
+
+	name := unique("contract-user")
+	if e := repo.Begin(); e != nil {
+		return fmt.Errorf("Begin: %v", e)
+	}
+	if e := repo.CreateUser(&po.User{Name: name, Money: 100, Created: now, Updated: now}); e != nil {
+		repo.Rollback()
+		return fmt.Errorf("CreateUser: %v", e)
+	}
+	if e := repo.Commit(); e != nil {
+		return fmt.Errorf("Commit: %v", e)
+	}
+	users, e := repo.UsersByNames([]string{name})
+	if e != nil {
+		return fmt.Errorf("UsersByNames: %v", e)
+	}
+	if users[name] == nil || users[name].Money != 100 {
+		return fmt.Errorf("UsersByNames: expected the created user")
+	}
+	return nil
+}
+
+// Export checks the filters and the primary key order of repo and of its detached copy, store writes the rows repo reads.
+func Export(repo domain.ExportRepository, store repositorytest.Store) error {
+	tag := unique("contract-export")
+	now := time.Now()
+	for _, stock := range []int{3, 8, 1, 0, 4} {
+		if e := store.Create(&po.Goods{Name: tag, Stock: stock, Tag: tag, Created: now, Updated: now}); e != nil {
+			return fmt.Errorf("Create: %v", e)
+		}
+	}
+
+	for _, item := range []struct {
+		name string
+		repo domain.ExportRepository
+	}{{"Goods", repo}, {"Detached Goods", repo.Detached()}} {
+		//批量大小小于结果数, 验证跨批次读取
+		rows := item.repo.Goods(2, "tag = ? AND stock < ?", tag, "4")
+		stocks := []int{}
+		lastID := 0
+		for {
+			row, ok, e := rows.Next()
+			if e != nil {
+				return fmt.Errorf("%s: %v", item.name, e)
+			}
+			if !ok {
+				break
+			}
+			goods := row.(*po.Goods)
+			if goods.ID <= lastID {
+				return fmt.Errorf("%s: expected the primary key order, got %d after %d", item.name, goods.ID, lastID)
+			}
+			lastID = goods.ID
+			stocks = append(stocks, goods.Stock)
+		}
+		if fmt.Sprint(stocks) != "[3 1 0]" {
+			return fmt.Errorf("%s: expected the stocks [3 1 0], got %v", item.name, stocks)
+		}
+	}
+	return nil
+}
+
+// Goods checks the missing goods, the conditional saves and the iteration of repo, store writes the rows repo reads.
+func Goods(repo domain.GoodsRepository, store repositorytest.Store) error {
+	for _, id := range []int{0, math.MaxInt32} {
+		if _, e := repo.Goods(id); e != gorm.ErrRecordNotFound {
+			return fmt.Errorf("Goods of the missing id %d: expected gorm.ErrRecordNotFound, got %v", id, e)
+		}
+	}
+
+	tag := unique("contract-goods")
+	now := time.Now()
+	created := &po.Goods{Name: tag, Price: 10, Stock: 1, Tag: tag, Created: now, Updated: now}
+	if e := store.Create(created); e != nil {
+		return fmt.Errorf("Create: %v", e)
+	}
+	goods, e := repo.Goods(created.ID)
+	if e != nil {
+		return fmt.Errorf("Goods: %v", e)
+	}
+	stale, e := repo.Goods(created.ID)
+	if e != nil {
+		return fmt.Errorf("Goods: %v", e)
+	}
+
+	version := goods.Version
+	goods.Stock = 999
+	goods.SetPrice(20)
+	if saved, e := repo.SaveGoods(goods, version); e != nil || !saved {
+		return fmt.Errorf("SaveGoods at the current version: saved %t, %v", saved, e)
+	}
+	if goods.Version != version+1 {
+		return fmt.Errorf("SaveGoods: expected version %d, got %d", version+1, goods.Version)
+	}
+	//版本已变化, 旧的副本不能覆盖
+	stale.SetPrice(30)
+	if saved, e := repo.SaveGoods(stale, stale.Version); e != nil || saved {
+		return fmt.Errorf("SaveGoods at a stale version: saved %t, %v", saved, e)
+	}
+	current, e := repo.Goods(created.ID)
+	if e != nil {
+		return fmt.Errorf("Goods after SaveGoods: %v", e)
+	}
+	if current.Price != 20 || current.Stock != 1 || current.Version != version+1 {
+		return fmt.Errorf("SaveGoods: expected price 20, stock 1 and version %d, got price %d, stock %d and version %d",
+			version+1, current.Price, current.Stock, current.Version)
+	}
+
+	rows, e := repo.AllGoods()
+	if e != nil {
+		return fmt.Errorf("AllGoods: %v", e)
+	}
+	if closer, ok := rows.(io.Closer); ok {
+		defer closer.Close()
+	}
+	for {
+		row, ok, e := rows.Next()
+		if e != nil {
+			return fmt.Errorf("AllGoods: %v", e)
+		}
+		if !ok {
+			return fmt.Errorf("AllGoods: goods %d is missing", created.ID)
+		}
+		if row.(*po.Goods).ID == created.ID {
+			return nil
+		}
+	}
+}
+
+// Order checks the missing orders and the conditional saves of repo, store writes the rows repo reads.
+func Order(repo domain.OrderRepository, store repositorytest.Store) error {
+	for _, id := range []int{0, math.MaxInt32} {
+		if _, e := repo.Order(id); e != gorm.ErrRecordNotFound {
+			return fmt.Errorf("Order of the missing id %d: expected gorm.ErrRecordNotFound, got %v", id, e)
+		}
+	}
+
+	now := time.Now()
+	created := &po.Order{OrderNo: unique("contract-order"), UserID: 1, TotalPrice: 10, Status: "未支付", Created: now, Updated: now}
+	if e := store.Create(created); e != nil {
+		return fmt.Errorf("Create: %v", e)
+	}
+	order, e := repo.Order(created.ID)
+	if e != nil {
+		return fmt.Errorf("Order: %v", e)
+	}
+	stale, e := repo.Order(created.ID)
+	if e != nil {
+		return fmt.Errorf("Order: %v", e)
+	}
+
+	version := order.Version
+	order.TotalPrice = 999
+	order.SetStatus("支付")
+	if saved, e := repo.SaveOrder(order, version); e != nil || !saved {
+		return fmt.Errorf("SaveOrder at the current version: saved %t, %v", saved, e)
+	}
+	if order.Version != version+1 {
+		return fmt.Errorf("SaveOrder: expected version %d, got %d", version+1, order.Version)
+	}
+	stale.SetStatus("完成")
+	if saved, e := repo.SaveOrder(stale, stale.Version); e != nil || saved {
+		return fmt.Errorf("SaveOrder at a stale version: saved %t, %v", saved, e)
+	}
+	current, e := repo.Order(created.ID)
+	if e != nil {
+		return fmt.Errorf("Order after SaveOrder: %v", e)
+	}
+	if current.Status != "支付" || current.TotalPrice != 10 || current.Version != version+1 {
+		return fmt.Errorf("SaveOrder: expected status 支付, total price 10 and version %d, got status %s, total price %d and version %d",
+			version+1, current.Status, current.TotalPrice, current.Version)
+	}
+	return nil
+}
+
+func expectPrices(list []*po.Goods, prices ...int) error {
+	got := []int{}
+	for _, goods := range list {
+		got = append(got, goods.Price)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(prices) {
+		return fmt.Errorf("expected the prices %v, got %v", prices, got)
+	}
+	return nil
+}
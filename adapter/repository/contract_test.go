@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/8treenet/dump/adapter/repository"
+	"github.com/8treenet/dump/adapter/repository/contract"
+	"github.com/8treenet/dump/adapter/repository/repositorytest"
+	"github.com/8treenet/dump/infra/apptest"
+	"github.com/8treenet/freedom"
+)
+
+var harness *apptest.Harness
+
+func TestMain(m *testing.M) {
+	var err error
+	if harness, err = apptest.New(apptest.Options{MigrationsDir: "../../server/migrations"}); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	harness.Close()
+	os.Exit(code)
+}
+
+// TestMemoryContract runs the contract checks on the in-memory fakes.
+func TestMemoryContract(t *testing.T) {
+	memory := repositorytest.NewMemoryStore()
+	if err := contract.Store(memory); err != nil {
+		t.Fatal(err)
+	}
+	if err := contract.Import(repositorytest.NewMemoryImport(memory), memory); err != nil {
+		t.Fatal(err)
+	}
+	if err := contract.Export(repositorytest.NewMemoryExport(memory), memory); err != nil {
+		t.Fatal(err)
+	}
+	if err := contract.Goods(repositorytest.NewMemoryGoods(memory), memory); err != nil {
+		t.Fatal(err)
+	}
+	if err := contract.Order(repositorytest.NewMemoryOrder(memory), memory); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGORMContract runs the contract checks on the gorm repositories, with the worker of a request.
+func TestGORMContract(t *testing.T) {
+	store := &repositorytest.GORMStore{DB: harness.DB}
+	if err := contract.Store(store); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	callErr := harness.Call(func(worker freedom.Worker) {
+		importRepo := &repository.Import{}
+		importRepo.Worker = worker
+		if err = contract.Import(importRepo, store); err != nil {
+			return
+		}
+		exportRepo := &repository.Export{}
+		exportRepo.Worker = worker
+		if err = contract.Export(exportRepo, store); err != nil {
+			return
+		}
+		goodsRepo := &repository.Goods{}
+		goodsRepo.Worker = worker
+		if err = contract.Goods(goodsRepo, store); err != nil {
+			return
+		}
+		orderRepo := &repository.Order{}
+		orderRepo.Worker = worker
+		err = contract.Order(orderRepo, store)
+	})
+	if callErr != nil {
+		t.Fatal(callErr)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
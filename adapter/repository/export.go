@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra/export"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindRepository(func() *Export {
+			return &Export{}
+		})
+	})
+}
+
+// Export reads the po lists of the admin exports in batches.
+type Export struct {
+	freedom.Repository
+	detached *gorm.DB
+}
+
+// Detached returns an Export of its own db that doesn't use the worker of the request,
+// for the background exports which read after the request is done. Its queries use context.Background().
+func (repo *Export) Detached() domain.ExportRepository {
+	var db *gorm.DB
+	if err := repo.FetchDB(&db); err != nil {
+		panic(err)
+	}
+	return &Export{detached: db}
+}
+
+// Orders .
+func (repo *Export) Orders(batchSize int, query string, args ...interface{}) export.Rows {
+	return newBatchIterator(repo, func() interface{} { return &[]*po.Order{} }, batchSize, query, args...)
+}
+
+// OrderDetails .
+func (repo *Export) OrderDetails(batchSize int, query string, args ...interface{}) export.Rows {
+	return newBatchIterator(repo, func() interface{} { return &[]*po.OrderDetail{} }, batchSize, query, args...)
+}
+
+// Deliveries .
+func (repo *Export) Deliveries(batchSize int, query string, args ...interface{}) export.Rows {
+	return newBatchIterator(repo, func() interface{} { return &[]*po.Delivery{} }, batchSize, query, args...)
+}
+
+// Goods .
+func (repo *Export) Goods(batchSize int, query string, args ...interface{}) export.Rows {
+	return newBatchIterator(repo, func() interface{} { return &[]*po.Goods{} }, batchSize, query, args...)
+}
+
+// db .
+func (repo *Export) db() *gorm.DB {
+	if repo.detached != nil {
+		return repo.detached.New()
+	}
+	var db *gorm.DB
+	if err := repo.FetchDB(&db); err != nil {
+		panic(err)
+	}
+	db = db.New()
+	db.SetLogger(repo.Worker.Logger())
+	return db
+}
@@ -1,3 +1,5 @@
+// Code generated by 'server generate'. DO NOT EDIT.
+
 package repository
 
 import (
@@ -5,259 +7,157 @@ import (
 	"github.com/8treenet/dump/domain/po"
 	"github.com/8treenet/freedom"
 	"github.com/jinzhu/gorm"
-	"strings"
 	"time"
 )
 
-// GORMRepository .
-type GORMRepository interface {
-	db() *gorm.DB
-	GetWorker() freedom.Worker
-}
-
-// Builder .
-type Builder interface {
-	Execute(db *gorm.DB, object interface{}) error
-}
-
-// Pager .
-type Pager struct {
-	pageSize  int
-	page      int
-	totalPage int
-	fields    []string
-	orders    []string
-}
-
-// NewDescPager .
-func NewDescPager(column string, columns ...string) *Pager {
-	return newDefaultPager("desc", column, columns...)
-}
-
-// NewAscPager .
-func NewAscPager(column string, columns ...string) *Pager {
-	return newDefaultPager("asc", column, columns...)
-}
-
-// NewDescOrder .
-func newDefaultPager(sort, field string, args ...string) *Pager {
-	fields := []string{field}
-	fields = append(fields, args...)
-	orders := []string{}
-	for index := 0; index < len(fields); index++ {
-		orders = append(orders, sort)
-	}
-	return &Pager{
-		fields: fields,
-		orders: orders,
-	}
-}
-
-// Order .
-func (p *Pager) Order() interface{} {
-	if len(p.fields) == 0 {
-		return nil
-	}
-	args := []string{}
-	for index := 0; index < len(p.fields); index++ {
-		args = append(args, fmt.Sprintf("`%s` %s", p.fields[index], p.orders[index]))
-	}
-
-	return strings.Join(args, ",")
-}
-
-// TotalPage .
-func (p *Pager) TotalPage() int {
-	return p.totalPage
-}
-
-// SetPage .
-func (p *Pager) SetPage(page, pageSize int) *Pager {
-	p.page = page
-	p.pageSize = pageSize
-	return p
-}
-
-// Execute .
-func (p *Pager) Execute(db *gorm.DB, object interface{}) (e error) {
-	pageFind := false
-	orderValue := p.Order()
-	if orderValue != nil {
-		db = db.Order(orderValue)
-	} else {
-		db = db.Set("gorm:order_by_primary_key", "DESC")
-	}
-	if p.page != 0 && p.pageSize != 0 {
-		pageFind = true
-		db = db.Offset((p.page - 1) * p.pageSize).Limit(p.pageSize)
-	}
-
-	resultDB := db.Find(object)
-	if resultDB.Error != nil {
-		return resultDB.Error
-	}
-
-	if !pageFind {
-		return
-	}
-
-	var count int
-	e = resultDB.Offset(0).Limit(1).Count(&count).Error
-	if e == nil && count != 0 {
-		//计算分页
-		if count%p.pageSize == 0 {
-			p.totalPage = count / p.pageSize
-		} else {
-			p.totalPage = count/p.pageSize + 1
-		}
-	}
-	return
-}
-
-func ormErrorLog(repo GORMRepository, model, method string, e error, expression ...interface{}) {
-	if e == nil || e == gorm.ErrRecordNotFound {
-		return
-	}
-	repo.GetWorker().Logger().Errorf("Orm error, model: %s, method: %s, expression :%v, reason for error:%v", model, method, expression, e)
-}
-
-// findOrderLog .
-func findOrderLog(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
+// findAdmin .
+func findAdmin(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLog", e, now)
-		ormErrorLog(repo, "OrderLog", "findOrderLog", e, result)
+		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdmin", e, now)
+		ormErrorLog(repo, "Admin", "findAdmin", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Admin", "findAdmin")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findOrderLogListByPrimarys .
-func findOrderLogListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
+// findAdminListByPrimarys .
+func findAdminListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
-	freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogListByPrimarys", e, now)
-	ormErrorLog(repo, "OrderLog", "findOrderLogsByPrimarys", e, primarys)
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Admin", "findAdminListByPrimarys").Find(results, primarys).Error
+	})
+	freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminListByPrimarys", e, now)
+	ormErrorLog(repo, "Admin", "findAdminsByPrimarys", e, primarys)
 	return
 }
 
-// findOrderLogByWhere .
-func findOrderLogByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
+// findAdminByWhere .
+func findAdminByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogByWhere", e, now)
-		ormErrorLog(repo, "OrderLog", "findOrderLogByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminByWhere", e, now)
+		ormErrorLog(repo, "Admin", "findAdminByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Admin", "findAdminByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findOrderLogByMap .
-func findOrderLogByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
+// findAdminByMap .
+func findAdminByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogByMap", e, now)
-		ormErrorLog(repo, "OrderLog", "findOrderLogByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminByMap", e, now)
+		ormErrorLog(repo, "Admin", "findAdminByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Admin", "findAdminByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findOrderLogList .
-func findOrderLogList(repo GORMRepository, query po.OrderLog, results interface{}, builders ...Builder) (e error) {
+// findAdminList .
+func findAdminList(repo GORMRepository, query po.Admin, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogList", e, now)
-		ormErrorLog(repo, "OrderLog", "findOrderLogs", e, query)
+		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminList", e, now)
+		ormErrorLog(repo, "Admin", "findAdmins", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Admin", "findAdminList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findOrderLogListByWhere .
-func findOrderLogListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
+// findAdminListByWhere .
+func findAdminListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogListByWhere", e, now)
-		ormErrorLog(repo, "OrderLog", "findOrderLogsByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminListByWhere", e, now)
+		ormErrorLog(repo, "Admin", "findAdminsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Admin", "findAdminListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findOrderLogListByMap .
-func findOrderLogListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
+// findAdminListByMap .
+func findAdminListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogListByMap", e, now)
-		ormErrorLog(repo, "OrderLog", "findOrderLogsByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminListByMap", e, now)
+		ormErrorLog(repo, "Admin", "findAdminsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Admin", "findAdminListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// createOrderLog .
-func createOrderLog(repo GORMRepository, object *po.OrderLog) (rowsAffected int64, e error) {
+// createAdmin .
+func createAdmin(repo GORMRepository, object *po.Admin) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Admin", "createAdmin").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
-	freedom.Prometheus().OrmWithLabelValues("OrderLog", "createOrderLog", e, now)
-	ormErrorLog(repo, "OrderLog", "createOrderLog", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("Admin", "createAdmin", e, now)
+	ormErrorLog(repo, "Admin", "createAdmin", e, *object)
 	return
 }
 
-// saveOrderLog .
-func saveOrderLog(repo GORMRepository, object *po.OrderLog) (affected int64, e error) {
+// saveAdmin .
+func saveAdmin(repo GORMRepository, object *po.Admin) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Admin", "saveAdmin").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
-	freedom.Prometheus().OrmWithLabelValues("OrderLog", "saveOrderLog", e, now)
-	ormErrorLog(repo, "OrderLog", "saveOrderLog", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("Admin", "saveAdmin", e, now)
+	ormErrorLog(repo, "Admin", "saveAdmin", e, *object)
 	return
 }
 
@@ -268,19 +168,22 @@ func findAlbums(repo GORMRepository, result interface{}, builders ...Builder) (e
 		freedom.Prometheus().OrmWithLabelValues("Albums", "findAlbums", e, now)
 		ormErrorLog(repo, "Albums", "findAlbums", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Albums", "findAlbums")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findAlbumsListByPrimarys .
 func findAlbumsListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Albums", "findAlbumsListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("Albums", "findAlbumsListByPrimarys", e, now)
 	ormErrorLog(repo, "Albums", "findAlbumssByPrimarys", e, primarys)
 	return
@@ -293,16 +196,17 @@ func findAlbumsByWhere(repo GORMRepository, query string, args []interface{}, re
 		freedom.Prometheus().OrmWithLabelValues("Albums", "findAlbumsByWhere", e, now)
 		ormErrorLog(repo, "Albums", "findAlbumsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Albums", "findAlbumsByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -314,13 +218,15 @@ func findAlbumsByMap(repo GORMRepository, query map[string]interface{}, result i
 		ormErrorLog(repo, "Albums", "findAlbumsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Albums", "findAlbumsByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -331,13 +237,15 @@ func findAlbumsList(repo GORMRepository, query po.Albums, results interface{}, b
 		freedom.Prometheus().OrmWithLabelValues("Albums", "findAlbumsList", e, now)
 		ormErrorLog(repo, "Albums", "findAlbumss", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Albums", "findAlbumsList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -348,16 +256,17 @@ func findAlbumsListByWhere(repo GORMRepository, query string, args []interface{}
 		freedom.Prometheus().OrmWithLabelValues("Albums", "findAlbumsListByWhere", e, now)
 		ormErrorLog(repo, "Albums", "findAlbumssByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Albums", "findAlbumsListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -369,20 +278,22 @@ func findAlbumsListByMap(repo GORMRepository, query map[string]interface{}, resu
 		ormErrorLog(repo, "Albums", "findAlbumssByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Albums", "findAlbumsListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createAlbums .
 func createAlbums(repo GORMRepository, object *po.Albums) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Albums", "createAlbums").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("Albums", "createAlbums", e, now)
@@ -393,7 +304,7 @@ func createAlbums(repo GORMRepository, object *po.Albums) (rowsAffected int64, e
 // saveAlbums .
 func saveAlbums(repo GORMRepository, object *po.Albums) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Albums", "saveAlbums").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("Albums", "saveAlbums", e, now)
@@ -408,19 +319,22 @@ func findCart(repo GORMRepository, result interface{}, builders ...Builder) (e e
 		freedom.Prometheus().OrmWithLabelValues("Cart", "findCart", e, now)
 		ormErrorLog(repo, "Cart", "findCart", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Cart", "findCart")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findCartListByPrimarys .
 func findCartListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Cart", "findCartListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("Cart", "findCartListByPrimarys", e, now)
 	ormErrorLog(repo, "Cart", "findCartsByPrimarys", e, primarys)
 	return
@@ -433,16 +347,17 @@ func findCartByWhere(repo GORMRepository, query string, args []interface{}, resu
 		freedom.Prometheus().OrmWithLabelValues("Cart", "findCartByWhere", e, now)
 		ormErrorLog(repo, "Cart", "findCartByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Cart", "findCartByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -454,13 +369,15 @@ func findCartByMap(repo GORMRepository, query map[string]interface{}, result int
 		ormErrorLog(repo, "Cart", "findCartByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Cart", "findCartByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -471,13 +388,15 @@ func findCartList(repo GORMRepository, query po.Cart, results interface{}, build
 		freedom.Prometheus().OrmWithLabelValues("Cart", "findCartList", e, now)
 		ormErrorLog(repo, "Cart", "findCarts", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Cart", "findCartList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -488,16 +407,17 @@ func findCartListByWhere(repo GORMRepository, query string, args []interface{},
 		freedom.Prometheus().OrmWithLabelValues("Cart", "findCartListByWhere", e, now)
 		ormErrorLog(repo, "Cart", "findCartsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Cart", "findCartListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -509,20 +429,22 @@ func findCartListByMap(repo GORMRepository, query map[string]interface{}, result
 		ormErrorLog(repo, "Cart", "findCartsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Cart", "findCartListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createCart .
 func createCart(repo GORMRepository, object *po.Cart) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Cart", "createCart").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("Cart", "createCart", e, now)
@@ -533,7 +455,7 @@ func createCart(repo GORMRepository, object *po.Cart) (rowsAffected int64, e err
 // saveCart .
 func saveCart(repo GORMRepository, object *po.Cart) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Cart", "saveCart").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("Cart", "saveCart", e, now)
@@ -548,19 +470,22 @@ func findDelivery(repo GORMRepository, result interface{}, builders ...Builder)
 		freedom.Prometheus().OrmWithLabelValues("Delivery", "findDelivery", e, now)
 		ormErrorLog(repo, "Delivery", "findDelivery", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Delivery", "findDelivery")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findDeliveryListByPrimarys .
 func findDeliveryListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Delivery", "findDeliveryListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("Delivery", "findDeliveryListByPrimarys", e, now)
 	ormErrorLog(repo, "Delivery", "findDeliverysByPrimarys", e, primarys)
 	return
@@ -573,16 +498,17 @@ func findDeliveryByWhere(repo GORMRepository, query string, args []interface{},
 		freedom.Prometheus().OrmWithLabelValues("Delivery", "findDeliveryByWhere", e, now)
 		ormErrorLog(repo, "Delivery", "findDeliveryByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Delivery", "findDeliveryByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -594,13 +520,15 @@ func findDeliveryByMap(repo GORMRepository, query map[string]interface{}, result
 		ormErrorLog(repo, "Delivery", "findDeliveryByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Delivery", "findDeliveryByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -611,13 +539,15 @@ func findDeliveryList(repo GORMRepository, query po.Delivery, results interface{
 		freedom.Prometheus().OrmWithLabelValues("Delivery", "findDeliveryList", e, now)
 		ormErrorLog(repo, "Delivery", "findDeliverys", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Delivery", "findDeliveryList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -628,16 +558,17 @@ func findDeliveryListByWhere(repo GORMRepository, query string, args []interface
 		freedom.Prometheus().OrmWithLabelValues("Delivery", "findDeliveryListByWhere", e, now)
 		ormErrorLog(repo, "Delivery", "findDeliverysByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Delivery", "findDeliveryListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -649,20 +580,22 @@ func findDeliveryListByMap(repo GORMRepository, query map[string]interface{}, re
 		ormErrorLog(repo, "Delivery", "findDeliverysByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Delivery", "findDeliveryListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createDelivery .
 func createDelivery(repo GORMRepository, object *po.Delivery) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Delivery", "createDelivery").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("Delivery", "createDelivery", e, now)
@@ -673,7 +606,7 @@ func createDelivery(repo GORMRepository, object *po.Delivery) (rowsAffected int6
 // saveDelivery .
 func saveDelivery(repo GORMRepository, object *po.Delivery) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Delivery", "saveDelivery").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("Delivery", "saveDelivery", e, now)
@@ -688,19 +621,22 @@ func findDump(repo GORMRepository, result interface{}, builders ...Builder) (e e
 		freedom.Prometheus().OrmWithLabelValues("Dump", "findDump", e, now)
 		ormErrorLog(repo, "Dump", "findDump", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Dump", "findDump")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findDumpListByPrimarys .
 func findDumpListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Dump", "findDumpListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("Dump", "findDumpListByPrimarys", e, now)
 	ormErrorLog(repo, "Dump", "findDumpsByPrimarys", e, primarys)
 	return
@@ -713,16 +649,17 @@ func findDumpByWhere(repo GORMRepository, query string, args []interface{}, resu
 		freedom.Prometheus().OrmWithLabelValues("Dump", "findDumpByWhere", e, now)
 		ormErrorLog(repo, "Dump", "findDumpByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Dump", "findDumpByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -734,13 +671,15 @@ func findDumpByMap(repo GORMRepository, query map[string]interface{}, result int
 		ormErrorLog(repo, "Dump", "findDumpByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Dump", "findDumpByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -751,13 +690,15 @@ func findDumpList(repo GORMRepository, query po.Dump, results interface{}, build
 		freedom.Prometheus().OrmWithLabelValues("Dump", "findDumpList", e, now)
 		ormErrorLog(repo, "Dump", "findDumps", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Dump", "findDumpList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -768,16 +709,17 @@ func findDumpListByWhere(repo GORMRepository, query string, args []interface{},
 		freedom.Prometheus().OrmWithLabelValues("Dump", "findDumpListByWhere", e, now)
 		ormErrorLog(repo, "Dump", "findDumpsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Dump", "findDumpListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -789,20 +731,22 @@ func findDumpListByMap(repo GORMRepository, query map[string]interface{}, result
 		ormErrorLog(repo, "Dump", "findDumpsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Dump", "findDumpListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createDump .
 func createDump(repo GORMRepository, object *po.Dump) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Dump", "createDump").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("Dump", "createDump", e, now)
@@ -813,7 +757,7 @@ func createDump(repo GORMRepository, object *po.Dump) (rowsAffected int64, e err
 // saveDump .
 func saveDump(repo GORMRepository, object *po.Dump) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Dump", "saveDump").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("Dump", "saveDump", e, now)
@@ -828,19 +772,22 @@ func findGoods(repo GORMRepository, result interface{}, builders ...Builder) (e
 		freedom.Prometheus().OrmWithLabelValues("Goods", "findGoods", e, now)
 		ormErrorLog(repo, "Goods", "findGoods", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Goods", "findGoods")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findGoodsListByPrimarys .
 func findGoodsListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Goods", "findGoodsListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("Goods", "findGoodsListByPrimarys", e, now)
 	ormErrorLog(repo, "Goods", "findGoodssByPrimarys", e, primarys)
 	return
@@ -853,16 +800,17 @@ func findGoodsByWhere(repo GORMRepository, query string, args []interface{}, res
 		freedom.Prometheus().OrmWithLabelValues("Goods", "findGoodsByWhere", e, now)
 		ormErrorLog(repo, "Goods", "findGoodsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Goods", "findGoodsByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -874,13 +822,15 @@ func findGoodsByMap(repo GORMRepository, query map[string]interface{}, result in
 		ormErrorLog(repo, "Goods", "findGoodsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Goods", "findGoodsByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -891,13 +841,15 @@ func findGoodsList(repo GORMRepository, query po.Goods, results interface{}, bui
 		freedom.Prometheus().OrmWithLabelValues("Goods", "findGoodsList", e, now)
 		ormErrorLog(repo, "Goods", "findGoodss", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Goods", "findGoodsList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -908,16 +860,17 @@ func findGoodsListByWhere(repo GORMRepository, query string, args []interface{},
 		freedom.Prometheus().OrmWithLabelValues("Goods", "findGoodsListByWhere", e, now)
 		ormErrorLog(repo, "Goods", "findGoodssByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Goods", "findGoodsListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -929,20 +882,22 @@ func findGoodsListByMap(repo GORMRepository, query map[string]interface{}, resul
 		ormErrorLog(repo, "Goods", "findGoodssByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Goods", "findGoodsListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createGoods .
 func createGoods(repo GORMRepository, object *po.Goods) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Goods", "createGoods").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("Goods", "createGoods", e, now)
@@ -953,7 +908,7 @@ func createGoods(repo GORMRepository, object *po.Goods) (rowsAffected int64, e e
 // saveGoods .
 func saveGoods(repo GORMRepository, object *po.Goods) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Goods", "saveGoods").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("Goods", "saveGoods", e, now)
@@ -968,19 +923,22 @@ func findOrder(repo GORMRepository, result interface{}, builders ...Builder) (e
 		freedom.Prometheus().OrmWithLabelValues("Order", "findOrder", e, now)
 		ormErrorLog(repo, "Order", "findOrder", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Order", "findOrder")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findOrderListByPrimarys .
 func findOrderListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Order", "findOrderListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("Order", "findOrderListByPrimarys", e, now)
 	ormErrorLog(repo, "Order", "findOrdersByPrimarys", e, primarys)
 	return
@@ -993,16 +951,17 @@ func findOrderByWhere(repo GORMRepository, query string, args []interface{}, res
 		freedom.Prometheus().OrmWithLabelValues("Order", "findOrderByWhere", e, now)
 		ormErrorLog(repo, "Order", "findOrderByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Order", "findOrderByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -1014,13 +973,15 @@ func findOrderByMap(repo GORMRepository, query map[string]interface{}, result in
 		ormErrorLog(repo, "Order", "findOrderByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Order", "findOrderByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -1031,13 +992,15 @@ func findOrderList(repo GORMRepository, query po.Order, results interface{}, bui
 		freedom.Prometheus().OrmWithLabelValues("Order", "findOrderList", e, now)
 		ormErrorLog(repo, "Order", "findOrders", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Order", "findOrderList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -1048,16 +1011,17 @@ func findOrderListByWhere(repo GORMRepository, query string, args []interface{},
 		freedom.Prometheus().OrmWithLabelValues("Order", "findOrderListByWhere", e, now)
 		ormErrorLog(repo, "Order", "findOrdersByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Order", "findOrderListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -1069,20 +1033,22 @@ func findOrderListByMap(repo GORMRepository, query map[string]interface{}, resul
 		ormErrorLog(repo, "Order", "findOrdersByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Order", "findOrderListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createOrder .
 func createOrder(repo GORMRepository, object *po.Order) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Order", "createOrder").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("Order", "createOrder", e, now)
@@ -1093,7 +1059,7 @@ func createOrder(repo GORMRepository, object *po.Order) (rowsAffected int64, e e
 // saveOrder .
 func saveOrder(repo GORMRepository, object *po.Order) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Order", "saveOrder").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("Order", "saveOrder", e, now)
@@ -1108,19 +1074,22 @@ func findOrderDetail(repo GORMRepository, result interface{}, builders ...Builde
 		freedom.Prometheus().OrmWithLabelValues("OrderDetail", "findOrderDetail", e, now)
 		ormErrorLog(repo, "OrderDetail", "findOrderDetail", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderDetail", "findOrderDetail")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findOrderDetailListByPrimarys .
 func findOrderDetailListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "OrderDetail", "findOrderDetailListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("OrderDetail", "findOrderDetailListByPrimarys", e, now)
 	ormErrorLog(repo, "OrderDetail", "findOrderDetailsByPrimarys", e, primarys)
 	return
@@ -1133,16 +1102,17 @@ func findOrderDetailByWhere(repo GORMRepository, query string, args []interface{
 		freedom.Prometheus().OrmWithLabelValues("OrderDetail", "findOrderDetailByWhere", e, now)
 		ormErrorLog(repo, "OrderDetail", "findOrderDetailByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderDetail", "findOrderDetailByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -1154,13 +1124,15 @@ func findOrderDetailByMap(repo GORMRepository, query map[string]interface{}, res
 		ormErrorLog(repo, "OrderDetail", "findOrderDetailByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderDetail", "findOrderDetailByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -1171,13 +1143,15 @@ func findOrderDetailList(repo GORMRepository, query po.OrderDetail, results inte
 		freedom.Prometheus().OrmWithLabelValues("OrderDetail", "findOrderDetailList", e, now)
 		ormErrorLog(repo, "OrderDetail", "findOrderDetails", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderDetail", "findOrderDetailList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -1188,16 +1162,17 @@ func findOrderDetailListByWhere(repo GORMRepository, query string, args []interf
 		freedom.Prometheus().OrmWithLabelValues("OrderDetail", "findOrderDetailListByWhere", e, now)
 		ormErrorLog(repo, "OrderDetail", "findOrderDetailsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderDetail", "findOrderDetailListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -1209,20 +1184,22 @@ func findOrderDetailListByMap(repo GORMRepository, query map[string]interface{},
 		ormErrorLog(repo, "OrderDetail", "findOrderDetailsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderDetail", "findOrderDetailListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createOrderDetail .
 func createOrderDetail(repo GORMRepository, object *po.OrderDetail) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "OrderDetail", "createOrderDetail").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("OrderDetail", "createOrderDetail", e, now)
@@ -1233,7 +1210,7 @@ func createOrderDetail(repo GORMRepository, object *po.OrderDetail) (rowsAffecte
 // saveOrderDetail .
 func saveOrderDetail(repo GORMRepository, object *po.OrderDetail) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "OrderDetail", "saveOrderDetail").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("OrderDetail", "saveOrderDetail", e, now)
@@ -1241,563 +1218,607 @@ func saveOrderDetail(repo GORMRepository, object *po.OrderDetail) (affected int6
 	return
 }
 
-// findProduct .
-func findProduct(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
+// findOrderLog .
+func findOrderLog(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Product", "findProduct", e, now)
-		ormErrorLog(repo, "Product", "findProduct", e, result)
+		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLog", e, now)
+		ormErrorLog(repo, "OrderLog", "findOrderLog", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderLog", "findOrderLog")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findProductListByPrimarys .
-func findProductListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
+// findOrderLogListByPrimarys .
+func findOrderLogListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
-	freedom.Prometheus().OrmWithLabelValues("Product", "findProductListByPrimarys", e, now)
-	ormErrorLog(repo, "Product", "findProductsByPrimarys", e, primarys)
-	return
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "OrderLog", "findOrderLogListByPrimarys").Find(results, primarys).Error
+	})
+	freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogListByPrimarys", e, now)
+	ormErrorLog(repo, "OrderLog", "findOrderLogsByPrimarys", e, primarys)
+	return
 }
 
-// findProductByWhere .
-func findProductByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
+// findOrderLogByWhere .
+func findOrderLogByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Product", "findProductByWhere", e, now)
-		ormErrorLog(repo, "Product", "findProductByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogByWhere", e, now)
+		ormErrorLog(repo, "OrderLog", "findOrderLogByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderLog", "findOrderLogByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findProductByMap .
-func findProductByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
+// findOrderLogByMap .
+func findOrderLogByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Product", "findProductByMap", e, now)
-		ormErrorLog(repo, "Product", "findProductByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogByMap", e, now)
+		ormErrorLog(repo, "OrderLog", "findOrderLogByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderLog", "findOrderLogByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findProductList .
-func findProductList(repo GORMRepository, query po.Product, results interface{}, builders ...Builder) (e error) {
+// findOrderLogList .
+func findOrderLogList(repo GORMRepository, query po.OrderLog, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Product", "findProductList", e, now)
-		ormErrorLog(repo, "Product", "findProducts", e, query)
+		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogList", e, now)
+		ormErrorLog(repo, "OrderLog", "findOrderLogs", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderLog", "findOrderLogList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findProductListByWhere .
-func findProductListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
+// findOrderLogListByWhere .
+func findOrderLogListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Product", "findProductListByWhere", e, now)
-		ormErrorLog(repo, "Product", "findProductsByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogListByWhere", e, now)
+		ormErrorLog(repo, "OrderLog", "findOrderLogsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderLog", "findOrderLogListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findProductListByMap .
-func findProductListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
+// findOrderLogListByMap .
+func findOrderLogListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Product", "findProductListByMap", e, now)
-		ormErrorLog(repo, "Product", "findProductsByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("OrderLog", "findOrderLogListByMap", e, now)
+		ormErrorLog(repo, "OrderLog", "findOrderLogsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "OrderLog", "findOrderLogListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// createProduct .
-func createProduct(repo GORMRepository, object *po.Product) (rowsAffected int64, e error) {
+// createOrderLog .
+func createOrderLog(repo GORMRepository, object *po.OrderLog) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "OrderLog", "createOrderLog").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
-	freedom.Prometheus().OrmWithLabelValues("Product", "createProduct", e, now)
-	ormErrorLog(repo, "Product", "createProduct", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("OrderLog", "createOrderLog", e, now)
+	ormErrorLog(repo, "OrderLog", "createOrderLog", e, *object)
 	return
 }
 
-// saveProduct .
-func saveProduct(repo GORMRepository, object *po.Product) (affected int64, e error) {
+// saveOrderLog .
+func saveOrderLog(repo GORMRepository, object *po.OrderLog) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "OrderLog", "saveOrderLog").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
-	freedom.Prometheus().OrmWithLabelValues("Product", "saveProduct", e, now)
-	ormErrorLog(repo, "Product", "saveProduct", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("OrderLog", "saveOrderLog", e, now)
+	ormErrorLog(repo, "OrderLog", "saveOrderLog", e, *object)
 	return
 }
 
-// findTestUsers .
-func findTestUsers(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
+// findProduct .
+func findProduct(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsers", e, now)
-		ormErrorLog(repo, "TestUsers", "findTestUsers", e, result)
+		freedom.Prometheus().OrmWithLabelValues("Product", "findProduct", e, now)
+		ormErrorLog(repo, "Product", "findProduct", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Product", "findProduct")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findTestUsersListByPrimarys .
-func findTestUsersListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
+// findProductListByPrimarys .
+func findProductListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
-	freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersListByPrimarys", e, now)
-	ormErrorLog(repo, "TestUsers", "findTestUserssByPrimarys", e, primarys)
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "Product", "findProductListByPrimarys").Find(results, primarys).Error
+	})
+	freedom.Prometheus().OrmWithLabelValues("Product", "findProductListByPrimarys", e, now)
+	ormErrorLog(repo, "Product", "findProductsByPrimarys", e, primarys)
 	return
 }
 
-// findTestUsersByWhere .
-func findTestUsersByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
+// findProductByWhere .
+func findProductByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersByWhere", e, now)
-		ormErrorLog(repo, "TestUsers", "findTestUsersByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("Product", "findProductByWhere", e, now)
+		ormErrorLog(repo, "Product", "findProductByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Product", "findProductByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findTestUsersByMap .
-func findTestUsersByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
+// findProductByMap .
+func findProductByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersByMap", e, now)
-		ormErrorLog(repo, "TestUsers", "findTestUsersByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("Product", "findProductByMap", e, now)
+		ormErrorLog(repo, "Product", "findProductByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Product", "findProductByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findTestUsersList .
-func findTestUsersList(repo GORMRepository, query po.TestUsers, results interface{}, builders ...Builder) (e error) {
+// findProductList .
+func findProductList(repo GORMRepository, query po.Product, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersList", e, now)
-		ormErrorLog(repo, "TestUsers", "findTestUserss", e, query)
+		freedom.Prometheus().OrmWithLabelValues("Product", "findProductList", e, now)
+		ormErrorLog(repo, "Product", "findProducts", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Product", "findProductList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findTestUsersListByWhere .
-func findTestUsersListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
+// findProductListByWhere .
+func findProductListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersListByWhere", e, now)
-		ormErrorLog(repo, "TestUsers", "findTestUserssByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("Product", "findProductListByWhere", e, now)
+		ormErrorLog(repo, "Product", "findProductsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Product", "findProductListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findTestUsersListByMap .
-func findTestUsersListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
+// findProductListByMap .
+func findProductListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersListByMap", e, now)
-		ormErrorLog(repo, "TestUsers", "findTestUserssByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("Product", "findProductListByMap", e, now)
+		ormErrorLog(repo, "Product", "findProductsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "Product", "findProductListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// createTestUsers .
-func createTestUsers(repo GORMRepository, object *po.TestUsers) (rowsAffected int64, e error) {
+// createProduct .
+func createProduct(repo GORMRepository, object *po.Product) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "Product", "createProduct").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
-	freedom.Prometheus().OrmWithLabelValues("TestUsers", "createTestUsers", e, now)
-	ormErrorLog(repo, "TestUsers", "createTestUsers", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("Product", "createProduct", e, now)
+	ormErrorLog(repo, "Product", "createProduct", e, *object)
 	return
 }
 
-// saveTestUsers .
-func saveTestUsers(repo GORMRepository, object *po.TestUsers) (affected int64, e error) {
+// saveProduct .
+func saveProduct(repo GORMRepository, object *po.Product) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "Product", "saveProduct").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
-	freedom.Prometheus().OrmWithLabelValues("TestUsers", "saveTestUsers", e, now)
-	ormErrorLog(repo, "TestUsers", "saveTestUsers", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("Product", "saveProduct", e, now)
+	ormErrorLog(repo, "Product", "saveProduct", e, *object)
 	return
 }
 
-// findAdmin .
-func findAdmin(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
+// findTestEmails .
+func findTestEmails(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdmin", e, now)
-		ormErrorLog(repo, "Admin", "findAdmin", e, result)
+		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmails", e, now)
+		ormErrorLog(repo, "TestEmails", "findTestEmails", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestEmails", "findTestEmails")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findAdminListByPrimarys .
-func findAdminListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
+// findTestEmailsListByPrimarys .
+func findTestEmailsListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
-	freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminListByPrimarys", e, now)
-	ormErrorLog(repo, "Admin", "findAdminsByPrimarys", e, primarys)
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "TestEmails", "findTestEmailsListByPrimarys").Find(results, primarys).Error
+	})
+	freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsListByPrimarys", e, now)
+	ormErrorLog(repo, "TestEmails", "findTestEmailssByPrimarys", e, primarys)
 	return
 }
 
-// findAdminByWhere .
-func findAdminByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
+// findTestEmailsByWhere .
+func findTestEmailsByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminByWhere", e, now)
-		ormErrorLog(repo, "Admin", "findAdminByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsByWhere", e, now)
+		ormErrorLog(repo, "TestEmails", "findTestEmailsByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestEmails", "findTestEmailsByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findAdminByMap .
-func findAdminByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
+// findTestEmailsByMap .
+func findTestEmailsByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminByMap", e, now)
-		ormErrorLog(repo, "Admin", "findAdminByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsByMap", e, now)
+		ormErrorLog(repo, "TestEmails", "findTestEmailsByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestEmails", "findTestEmailsByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findAdminList .
-func findAdminList(repo GORMRepository, query po.Admin, results interface{}, builders ...Builder) (e error) {
+// findTestEmailsList .
+func findTestEmailsList(repo GORMRepository, query po.TestEmails, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminList", e, now)
-		ormErrorLog(repo, "Admin", "findAdmins", e, query)
+		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsList", e, now)
+		ormErrorLog(repo, "TestEmails", "findTestEmailss", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestEmails", "findTestEmailsList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findAdminListByWhere .
-func findAdminListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
+// findTestEmailsListByWhere .
+func findTestEmailsListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminListByWhere", e, now)
-		ormErrorLog(repo, "Admin", "findAdminsByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsListByWhere", e, now)
+		ormErrorLog(repo, "TestEmails", "findTestEmailssByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestEmails", "findTestEmailsListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findAdminListByMap .
-func findAdminListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
+// findTestEmailsListByMap .
+func findTestEmailsListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("Admin", "findAdminListByMap", e, now)
-		ormErrorLog(repo, "Admin", "findAdminsByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsListByMap", e, now)
+		ormErrorLog(repo, "TestEmails", "findTestEmailssByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestEmails", "findTestEmailsListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// createAdmin .
-func createAdmin(repo GORMRepository, object *po.Admin) (rowsAffected int64, e error) {
+// createTestEmails .
+func createTestEmails(repo GORMRepository, object *po.TestEmails) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "TestEmails", "createTestEmails").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
-	freedom.Prometheus().OrmWithLabelValues("Admin", "createAdmin", e, now)
-	ormErrorLog(repo, "Admin", "createAdmin", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("TestEmails", "createTestEmails", e, now)
+	ormErrorLog(repo, "TestEmails", "createTestEmails", e, *object)
 	return
 }
 
-// saveAdmin .
-func saveAdmin(repo GORMRepository, object *po.Admin) (affected int64, e error) {
+// saveTestEmails .
+func saveTestEmails(repo GORMRepository, object *po.TestEmails) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "TestEmails", "saveTestEmails").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
-	freedom.Prometheus().OrmWithLabelValues("Admin", "saveAdmin", e, now)
-	ormErrorLog(repo, "Admin", "saveAdmin", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("TestEmails", "saveTestEmails", e, now)
+	ormErrorLog(repo, "TestEmails", "saveTestEmails", e, *object)
 	return
 }
 
-// findTestEmails .
-func findTestEmails(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
+// findTestUsers .
+func findTestUsers(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmails", e, now)
-		ormErrorLog(repo, "TestEmails", "findTestEmails", e, result)
+		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsers", e, now)
+		ormErrorLog(repo, "TestUsers", "findTestUsers", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestUsers", "findTestUsers")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findTestEmailsListByPrimarys .
-func findTestEmailsListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
+// findTestUsersListByPrimarys .
+func findTestUsersListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
-	freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsListByPrimarys", e, now)
-	ormErrorLog(repo, "TestEmails", "findTestEmailssByPrimarys", e, primarys)
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "TestUsers", "findTestUsersListByPrimarys").Find(results, primarys).Error
+	})
+	freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersListByPrimarys", e, now)
+	ormErrorLog(repo, "TestUsers", "findTestUserssByPrimarys", e, primarys)
 	return
 }
 
-// findTestEmailsByWhere .
-func findTestEmailsByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
+// findTestUsersByWhere .
+func findTestUsersByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsByWhere", e, now)
-		ormErrorLog(repo, "TestEmails", "findTestEmailsByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersByWhere", e, now)
+		ormErrorLog(repo, "TestUsers", "findTestUsersByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestUsers", "findTestUsersByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findTestEmailsByMap .
-func findTestEmailsByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
+// findTestUsersByMap .
+func findTestUsersByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsByMap", e, now)
-		ormErrorLog(repo, "TestEmails", "findTestEmailsByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersByMap", e, now)
+		ormErrorLog(repo, "TestUsers", "findTestUsersByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestUsers", "findTestUsersByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
-// findTestEmailsList .
-func findTestEmailsList(repo GORMRepository, query po.TestEmails, results interface{}, builders ...Builder) (e error) {
+// findTestUsersList .
+func findTestUsersList(repo GORMRepository, query po.TestUsers, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsList", e, now)
-		ormErrorLog(repo, "TestEmails", "findTestEmailss", e, query)
+		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersList", e, now)
+		ormErrorLog(repo, "TestUsers", "findTestUserss", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestUsers", "findTestUsersList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findTestEmailsListByWhere .
-func findTestEmailsListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
+// findTestUsersListByWhere .
+func findTestUsersListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsListByWhere", e, now)
-		ormErrorLog(repo, "TestEmails", "findTestEmailssByWhere", e, query, args)
+		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersListByWhere", e, now)
+		ormErrorLog(repo, "TestUsers", "findTestUserssByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestUsers", "findTestUsersListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// findTestEmailsListByMap .
-func findTestEmailsListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
+// findTestUsersListByMap .
+func findTestUsersListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
 	now := time.Now()
 	defer func() {
-		freedom.Prometheus().OrmWithLabelValues("TestEmails", "findTestEmailsListByMap", e, now)
-		ormErrorLog(repo, "TestEmails", "findTestEmailssByMap", e, query)
+		freedom.Prometheus().OrmWithLabelValues("TestUsers", "findTestUsersListByMap", e, now)
+		ormErrorLog(repo, "TestUsers", "findTestUserssByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "TestUsers", "findTestUsersListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
-// createTestEmails .
-func createTestEmails(repo GORMRepository, object *po.TestEmails) (rowsAffected int64, e error) {
+// createTestUsers .
+func createTestUsers(repo GORMRepository, object *po.TestUsers) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "TestUsers", "createTestUsers").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
-	freedom.Prometheus().OrmWithLabelValues("TestEmails", "createTestEmails", e, now)
-	ormErrorLog(repo, "TestEmails", "createTestEmails", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("TestUsers", "createTestUsers", e, now)
+	ormErrorLog(repo, "TestUsers", "createTestUsers", e, *object)
 	return
 }
 
-// saveTestEmails .
-func saveTestEmails(repo GORMRepository, object *po.TestEmails) (affected int64, e error) {
+// saveTestUsers .
+func saveTestUsers(repo GORMRepository, object *po.TestUsers) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "TestUsers", "saveTestUsers").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
-	freedom.Prometheus().OrmWithLabelValues("TestEmails", "saveTestEmails", e, now)
-	ormErrorLog(repo, "TestEmails", "saveTestEmails", e, *object)
+	freedom.Prometheus().OrmWithLabelValues("TestUsers", "saveTestUsers", e, now)
+	ormErrorLog(repo, "TestUsers", "saveTestUsers", e, *object)
 	return
 }
 
@@ -1808,19 +1829,22 @@ func findUser(repo GORMRepository, result interface{}, builders ...Builder) (e e
 		freedom.Prometheus().OrmWithLabelValues("User", "findUser", e, now)
 		ormErrorLog(repo, "User", "findUser", e, result)
 	}()
-	db := repo.db()
-	if len(builders) == 0 {
-		e = db.Where(result).Last(result).Error
-		return
-	}
-	e = builders[0].Execute(db.Limit(1), result)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "User", "findUser")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
 // findUserListByPrimarys .
 func findUserListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
 	now := time.Now()
-	e = repo.db().Find(results, primarys).Error
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, "User", "findUserListByPrimarys").Find(results, primarys).Error
+	})
 	freedom.Prometheus().OrmWithLabelValues("User", "findUserListByPrimarys", e, now)
 	ormErrorLog(repo, "User", "findUsersByPrimarys", e, primarys)
 	return
@@ -1833,16 +1857,17 @@ func findUserByWhere(repo GORMRepository, query string, args []interface{}, resu
 		freedom.Prometheus().OrmWithLabelValues("User", "findUserByWhere", e, now)
 		ormErrorLog(repo, "User", "findUserByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "User", "findUserByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -1854,13 +1879,15 @@ func findUserByMap(repo GORMRepository, query map[string]interface{}, result int
 		ormErrorLog(repo, "User", "findUserByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
-	if len(builders) == 0 {
-		e = db.Last(result).Error
-		return
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "User", "findUserByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
 
-	e = builders[0].Execute(db.Limit(1), result)
+		return builders[0].Execute(db.Limit(1), result)
+	})
 	return
 }
 
@@ -1871,13 +1898,15 @@ func findUserList(repo GORMRepository, query po.User, results interface{}, build
 		freedom.Prometheus().OrmWithLabelValues("User", "findUserList", e, now)
 		ormErrorLog(repo, "User", "findUsers", e, query)
 	}()
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "User", "findUserList")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -1888,16 +1917,17 @@ func findUserListByWhere(repo GORMRepository, query string, args []interface{},
 		freedom.Prometheus().OrmWithLabelValues("User", "findUserListByWhere", e, now)
 		ormErrorLog(repo, "User", "findUsersByWhere", e, query, args)
 	}()
-	db := repo.db()
-	if query != "" {
-		db = db.Where(query, args...)
-	}
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "User", "findUserListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
@@ -1909,20 +1939,22 @@ func findUserListByMap(repo GORMRepository, query map[string]interface{}, result
 		ormErrorLog(repo, "User", "findUsersByMap", e, query)
 	}()
 
-	db := repo.db().Where(query)
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, "User", "findUserListByMap")
+		db = db.Where(query)
 
-	if len(builders) == 0 {
-		e = db.Find(results).Error
-		return
-	}
-	e = builders[0].Execute(db, results)
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
 	return
 }
 
 // createUser .
 func createUser(repo GORMRepository, object *po.User) (rowsAffected int64, e error) {
 	now := time.Now()
-	db := repo.db().Create(object)
+	db := ormDB(repo, repo.db(), "User", "createUser").Create(object)
 	rowsAffected = db.RowsAffected
 	e = db.Error
 	freedom.Prometheus().OrmWithLabelValues("User", "createUser", e, now)
@@ -1933,10 +1965,76 @@ func createUser(repo GORMRepository, object *po.User) (rowsAffected int64, e err
 // saveUser .
 func saveUser(repo GORMRepository, object *po.User) (affected int64, e error) {
 	now := time.Now()
-	db := repo.db().Model(object).Updates(object.TakeChanges())
+	db := ormDB(repo, repo.db(), "User", "saveUser").Model(object).Updates(object.TakeChanges())
 	e = db.Error
 	affected = db.RowsAffected
 	freedom.Prometheus().OrmWithLabelValues("User", "saveUser", e, now)
 	ormErrorLog(repo, "User", "saveUser", e, *object)
 	return
 }
+
+// create calls the create function of the po type of object.
+func create(repo GORMRepository, object interface{}) (int64, error) {
+	switch object := object.(type) {
+	case *po.Admin:
+		return createAdmin(repo, object)
+	case *po.Albums:
+		return createAlbums(repo, object)
+	case *po.Cart:
+		return createCart(repo, object)
+	case *po.Delivery:
+		return createDelivery(repo, object)
+	case *po.Dump:
+		return createDump(repo, object)
+	case *po.Goods:
+		return createGoods(repo, object)
+	case *po.Order:
+		return createOrder(repo, object)
+	case *po.OrderDetail:
+		return createOrderDetail(repo, object)
+	case *po.OrderLog:
+		return createOrderLog(repo, object)
+	case *po.Product:
+		return createProduct(repo, object)
+	case *po.TestEmails:
+		return createTestEmails(repo, object)
+	case *po.TestUsers:
+		return createTestUsers(repo, object)
+	case *po.User:
+		return createUser(repo, object)
+	}
+	return 0, fmt.Errorf("no create function of %T", object)
+}
+
+// save calls the save function of the po type of object.
+func save(repo GORMRepository, object interface{}) (int64, error) {
+	switch object := object.(type) {
+	case *po.Admin:
+		return saveAdmin(repo, object)
+	case *po.Albums:
+		return saveAlbums(repo, object)
+	case *po.Cart:
+		return saveCart(repo, object)
+	case *po.Delivery:
+		return saveDelivery(repo, object)
+	case *po.Dump:
+		return saveDump(repo, object)
+	case *po.Goods:
+		return saveGoods(repo, object)
+	case *po.Order:
+		return saveOrder(repo, object)
+	case *po.OrderDetail:
+		return saveOrderDetail(repo, object)
+	case *po.OrderLog:
+		return saveOrderLog(repo, object)
+	case *po.Product:
+		return saveProduct(repo, object)
+	case *po.TestEmails:
+		return saveTestEmails(repo, object)
+	case *po.TestUsers:
+		return saveTestUsers(repo, object)
+	case *po.User:
+		return saveUser(repo, object)
+	}
+	return 0, fmt.Errorf("no save function of %T", object)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindRepository(func() *Goods {
+			return &Goods{}
+		})
+	})
+}
+
+// Goods reads and updates goods, an update only applies to the version it was checked against.
+type Goods struct {
+	freedom.Repository
+}
+
+// Goods returns the goods of id from the primary, gorm.ErrRecordNotFound when there is none.
+func (repo *Goods) Goods(id int) (*po.Goods, error) {
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound //零值主键不会成为查询条件
+	}
+	result := &po.Goods{ID: id}
+	if e := findGoods(repo, result, Primary()); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// AllGoods iterates the goods row by row, the iterator must be closed.
+func (repo *Goods) AllGoods() (infra.RowIterator, error) {
+	it, e := newIterator(repo, func() interface{} { return &po.Goods{} }, "")
+	if e != nil {
+		return nil, e
+	}
+	return it, nil
+}
+
+// SaveGoods saves the changes of goods if its row is still at version.
+func (repo *Goods) SaveGoods(goods *po.Goods, version int) (bool, error) {
+	goods.AddVersion(1)
+	return saveIfUnchanged(repo, "Goods", goods, goods.TakeChanges(), version)
+}
+
+// db .
+func (repo *Goods) db() *gorm.DB {
+	var db *gorm.DB
+	if err := repo.FetchDB(&db); err != nil {
+		panic(err)
+	}
+	db = db.New()
+	db.SetLogger(repo.Worker.Logger())
+	return db
+}
+
+// saveIfUnchanged updates the changed columns of object where its version column still is version,
+// it reports false when another write came first. The changes must increment the version.
+func saveIfUnchanged(repo GORMRepository, model string, object interface{}, changes map[string]interface{}, version int) (saved bool, e error) {
+	now := time.Now()
+	db := ormDB(repo, repo.db(), model, "save"+model+"IfUnchanged").Model(object).Where("version = ?", version).Updates(changes)
+	e = db.Error
+	freedom.Prometheus().OrmWithLabelValues(model, "save"+model+"IfUnchanged", e, now)
+	ormErrorLog(repo, model, "save"+model+"IfUnchanged", e, object)
+	return e == nil && db.RowsAffected > 0, e
+}
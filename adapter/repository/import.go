@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindRepository(func() *Import {
+			return &Import{}
+		})
+	})
+}
+
+// Import writes imported goods and users, every batch runs in its own transaction, a dry run in a single one.
+type Import struct {
+	freedom.Repository
+	tx *gorm.DB
+}
+
+// Begin starts the transaction of a batch.
+func (repo *Import) Begin() error {
+	repo.tx = repo.db().Begin()
+	return repo.tx.Error
+}
+
+// Commit commits the batch.
+func (repo *Import) Commit() error {
+	tx := repo.tx
+	repo.tx = nil
+	return tx.Commit().Error
+}
+
+// Rollback .
+func (repo *Import) Rollback() error {
+	tx := repo.tx
+	repo.tx = nil
+	return tx.Rollback().Error
+}
+
+// GoodsByNames returns the existing goods keyed by name.
+func (repo *Import) GoodsByNames(names []string) (map[string]*po.Goods, error) {
+	list := []*po.Goods{}
+	if e := findGoodsListByWhere(repo, "name IN (?)", []interface{}{names}, &list); e != nil {
+		return nil, e
+	}
+	result := map[string]*po.Goods{}
+	for _, goods := range list {
+		result[goods.Name] = goods
+	}
+	return result, nil
+}
+
+// CreateGoods .
+func (repo *Import) CreateGoods(goods *po.Goods) error {
+	_, e := createGoods(repo, goods)
+	return e
+}
+
+// SaveGoods .
+func (repo *Import) SaveGoods(goods *po.Goods) error {
+	_, e := saveGoods(repo, goods)
+	return e
+}
+
+// UsersByNames returns the existing users keyed by name.
+func (repo *Import) UsersByNames(names []string) (map[string]*po.User, error) {
+	list := []*po.User{}
+	if e := findUserListByWhere(repo, "name IN (?)", []interface{}{names}, &list); e != nil {
+		return nil, e
+	}
+	result := map[string]*po.User{}
+	for _, user := range list {
+		result[user.Name] = user
+	}
+	return result, nil
+}
+
+// CreateUser .
+func (repo *Import) CreateUser(user *po.User) error {
+	_, e := createUser(repo, user)
+	return e
+}
+
+// SaveUser .
+func (repo *Import) SaveUser(user *po.User) error {
+	_, e := saveUser(repo, user)
+	return e
+}
+
+// db returns the transaction of the current batch, the worker's DB outside of a batch.
+func (repo *Import) db() *gorm.DB {
+	if repo.tx != nil {
+		return repo.tx
+	}
+	var db *gorm.DB
+	if err := repo.FetchDB(&db); err != nil {
+		panic(err)
+	}
+	db = db.New()
+	db.SetLogger(repo.Worker.Logger())
+	return db
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"reflect"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Iterator reads query results row by row, so that large exports don't load the whole table into memory.
+// It implements infra.RowIterator, Close must be called once the iteration is done.
+type Iterator struct {
+	db        *gorm.DB
+	rows      *sql.Rows
+	newObject func() interface{}
+}
+
+// newIterator . newObject returns a pointer to a new po, e.g. func() interface{} { return &po.Goods{} }.
+func newIterator(repo GORMRepository, newObject func() interface{}, query string, args ...interface{}) (*Iterator, error) {
+	result := &Iterator{newObject: newObject}
+	e := read(repo, nil, func(db *gorm.DB, _ []Builder) (e error) {
+		db = ormDB(repo, db, "", "Iterator").Model(newObject())
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		result.db = db
+		result.rows, e = db.Rows()
+		return
+	})
+	if e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// Next .
+func (it *Iterator) Next() (interface{}, bool, error) {
+	if !it.rows.Next() {
+		return nil, false, it.rows.Err()
+	}
+	object := it.newObject()
+	if e := it.db.ScanRows(it.rows, object); e != nil {
+		return nil, false, e
+	}
+	return object, true, nil
+}
+
+// Close .
+func (it *Iterator) Close() error {
+	return it.rows.Close()
+}
+
+// BatchIterator reads rows in primary key order, batchSize rows per query, so that a long export
+// neither keeps a cursor open nor loads the whole table. It implements infra.RowIterator.
+// The primary key must be the ID field of the po.
+type BatchIterator struct {
+	db        *gorm.DB
+	primary   *gorm.DB
+	replica   *replica
+	newSlice  func() interface{}
+	batchSize int
+	lastID    int64
+	items     reflect.Value
+	index     int
+	done      bool
+}
+
+// newBatchIterator . newSlice returns a pointer to a new po slice, e.g. func() interface{} { return &[]*po.Goods{} }.
+// A batch failing on a replica is read again from the primary, which serves the rest of the iteration.
+func newBatchIterator(repo GORMRepository, newSlice func() interface{}, batchSize int, query string, args ...interface{}) *BatchIterator {
+	where := func(db *gorm.DB) *gorm.DB {
+		db = ormDB(repo, db, "", "BatchIterator")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		return db
+	}
+	db, _, item := readDB(repo, nil)
+	result := &BatchIterator{db: where(db), newSlice: newSlice, batchSize: batchSize}
+	if item != nil {
+		result.primary = where(repo.db())
+		result.replica = item
+	}
+	return result
+}
+
+// Next .
+func (it *BatchIterator) Next() (interface{}, bool, error) {
+	if !it.items.IsValid() || it.index >= it.items.Len() {
+		if it.done {
+			return nil, false, nil
+		}
+		slice, e := it.batch(it.db)
+		if e != nil && it.replica != nil {
+			replicaErr := e
+			if slice, e = it.batch(it.primary); e == nil {
+				it.replica.fail(replicaErr)
+				it.db, it.replica = it.primary, nil
+			}
+		}
+		if e != nil {
+			return nil, false, e
+		}
+		it.items = reflect.ValueOf(slice).Elem()
+		it.index = 0
+		it.done = it.items.Len() < it.batchSize
+		if it.items.Len() == 0 {
+			return nil, false, nil
+		}
+	}
+	item := it.items.Index(it.index)
+	it.index++
+	it.lastID = reflect.Indirect(item).FieldByName("ID").Int()
+	return item.Interface(), true, nil
+}
+
+// batch reads the rows following lastID.
+func (it *BatchIterator) batch(db *gorm.DB) (interface{}, error) {
+	slice := it.newSlice()
+	return slice, db.Where("id > ?", it.lastID).Order("id").Limit(it.batchSize).Find(slice).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindRepository(func() *Order {
+			return &Order{}
+		})
+	})
+}
+
+// Order reads and updates orders, an update only applies to the version it was checked against.
+type Order struct {
+	freedom.Repository
+}
+
+// Order returns the order of id from the primary, gorm.ErrRecordNotFound when there is none.
+func (repo *Order) Order(id int) (*po.Order, error) {
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound //零值主键不会成为查询条件
+	}
+	result := &po.Order{ID: id}
+	if e := findOrder(repo, result, Primary()); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// SaveOrder saves the changes of order if its row is still at version.
+func (repo *Order) SaveOrder(order *po.Order, version int) (bool, error) {
+	order.AddVersion(1)
+	return saveIfUnchanged(repo, "Order", order, order.TakeChanges(), version)
+}
+
+// db .
+func (repo *Order) db() *gorm.DB {
+	var db *gorm.DB
+	if err := repo.FetchDB(&db); err != nil {
+		panic(err)
+	}
+	db = db.New()
+	db.SetLogger(repo.Worker.Logger())
+	return db
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	replicas    []*replica
+	replicaStop chan struct{}
+)
+
+type replica struct {
+	index   int
+	db      *gorm.DB
+	healthy int32
+}
+
+var replicaNext uint32
+
+// InstallReplicas installs the read replicas used by the find helpers.
+// Each replica is pinged every checkInterval, unhealthy replicas are skipped until they recover.
+// Installing again stops the checks of the previous replicas.
+func InstallReplicas(dbs []*gorm.DB, checkInterval time.Duration) {
+	StopReplicas()
+	replicas = nil
+	for index, db := range dbs {
+		replicas = append(replicas, &replica{index: index, db: db, healthy: 1})
+	}
+	if len(replicas) == 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	replicaStop = stop
+	ticker := time.NewTicker(checkInterval)
+	go func(list []*replica) {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			for _, item := range list {
+				var healthy int32 = 1
+				if e := item.db.DB().Ping(); e != nil {
+					healthy = 0
+					freedom.Logger().Errorf("replica %d ping failed: %v", item.index, e)
+				}
+				atomic.StoreInt32(&item.healthy, healthy)
+			}
+		}
+	}(replicas)
+}
+
+// StopReplicas stops the health checks of the replicas, e.g. before closing them.
+func StopReplicas() {
+	if replicaStop != nil {
+		close(replicaStop)
+		replicaStop = nil
+	}
+}
+
+// pickReplica returns the next healthy replica in round-robin order, nil when none is healthy.
+func pickReplica() *replica {
+	count := uint32(len(replicas))
+	if count == 0 {
+		return nil
+	}
+	start := atomic.AddUint32(&replicaNext, 1)
+	for index := uint32(0); index < count; index++ {
+		item := replicas[(start+index)%count]
+		if atomic.LoadInt32(&item.healthy) == 1 {
+			return item
+		}
+	}
+	return nil
+}
+
+// fail marks the replica unhealthy after a query failed on it, the next successful ping restores it.
+func (r *replica) fail(e error) {
+	if atomic.SwapInt32(&r.healthy, 0) == 1 {
+		freedom.Logger().Errorf("replica %d query failed, retried on the primary: %v", r.index, e)
+	}
+}
+
+// PrimaryBuilder forces a find helper to read from the primary, so that a request can read its own writes.
+type PrimaryBuilder struct {
+	Builder Builder
+}
+
+// Primary . builder is optional, e.g. findGoodsList(repo, query, &results, Primary(pager)).
+func Primary(builder ...Builder) Builder {
+	result := &PrimaryBuilder{}
+	if len(builder) > 0 {
+		result.Builder = builder[0]
+	}
+	return result
+}
+
+// Execute .
+func (b *PrimaryBuilder) Execute(db *gorm.DB, object interface{}) error {
+	if b.Builder == nil {
+		return db.Find(object).Error
+	}
+	return b.Builder.Execute(db, object)
+}
+
+// readDB returns the db of a find helper, the remaining builders and the replica of the db, nil for the primary.
+// Reads go to a replica unless a transaction is running, no replica is healthy, or PrimaryBuilder is given.
+func readDB(repo GORMRepository, builders []Builder) (*gorm.DB, []Builder, *replica) {
+	db := repo.db()
+	if len(builders) > 0 {
+		if primary, ok := builders[0].(*PrimaryBuilder); ok {
+			if primary.Builder == nil {
+				return db, nil, nil
+			}
+			return db, []Builder{primary.Builder}, nil
+		}
+	}
+	if _, ok := db.CommonDB().(*sql.Tx); ok {
+		return db, builders, nil
+	}
+
+	item := pickReplica()
+	if item == nil {
+		return db, builders, nil
+	}
+	replicaDB := item.db.New()
+	if worker := repo.GetWorker(); worker != nil {
+		replicaDB.SetLogger(worker.Logger())
+	}
+	return replicaDB, builders, item
+}
+
+// read runs the query of a find helper on the db of readDB. A query failing on a replica is run again
+// on the primary, the replica is marked unhealthy when the primary succeeds, the error is the query's otherwise.
+func read(repo GORMRepository, builders []Builder, query func(db *gorm.DB, builders []Builder) error) error {
+	db, builders, item := readDB(repo, builders)
+	e := query(db, builders)
+	if item == nil || e == nil || gorm.IsRecordNotFoundError(e) {
+		return e
+	}
+	if primaryErr := query(repo.db(), builders); primaryErr != nil {
+		return primaryErr
+	}
+	item.fail(e)
+	return nil
+}
@@ -0,0 +1,264 @@
+package repositorytest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/8treenet/dump/domain"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/export"
+	"github.com/jinzhu/gorm"
+)
+
+// The in-memory fakes of the repositories, for the tests of the services.
+// They are not bound to the framework, a test sets them on the service, e.g.
+// service := &domain.Import{ImportRepo: repositorytest.NewMemoryImport(store)}.
+
+// MemoryDefault .
+type MemoryDefault struct {
+	IP string
+	UA string
+}
+
+// GetIP .
+func (repo *MemoryDefault) GetIP() string {
+	return repo.IP
+}
+
+// GetUA .
+func (repo *MemoryDefault) GetUA() string {
+	return repo.UA
+}
+
+// MemoryExport .
+type MemoryExport struct {
+	Store *MemoryStore
+}
+
+// NewMemoryExport .
+func NewMemoryExport(store *MemoryStore) *MemoryExport {
+	return &MemoryExport{Store: store}
+}
+
+// Orders .
+func (repo *MemoryExport) Orders(batchSize int, query string, args ...interface{}) export.Rows {
+	return repo.rows(&[]*po.Order{}, query, args)
+}
+
+// OrderDetails .
+func (repo *MemoryExport) OrderDetails(batchSize int, query string, args ...interface{}) export.Rows {
+	return repo.rows(&[]*po.OrderDetail{}, query, args)
+}
+
+// Deliveries .
+func (repo *MemoryExport) Deliveries(batchSize int, query string, args ...interface{}) export.Rows {
+	return repo.rows(&[]*po.Delivery{}, query, args)
+}
+
+// Goods .
+func (repo *MemoryExport) Goods(batchSize int, query string, args ...interface{}) export.Rows {
+	return repo.rows(&[]*po.Goods{}, query, args)
+}
+
+// Detached returns repo, the store doesn't depend on a request.
+func (repo *MemoryExport) Detached() domain.ExportRepository {
+	return repo
+}
+
+func (repo *MemoryExport) rows(results interface{}, query string, args []interface{}) export.Rows {
+	return newMemoryRows(repo.Store, results, query, args)
+}
+
+// newMemoryRows reads the matching rows at once, in primary key order as BatchIterator.
+func newMemoryRows(store *MemoryStore, results interface{}, query string, args []interface{}) *memoryRows {
+	if e := store.FindListByWhere(query, args, results); e != nil {
+		return &memoryRows{e: e}
+	}
+	items := reflect.ValueOf(results).Elem()
+	sort.SliceStable(items.Interface(), func(i, j int) bool {
+		return primaryField(items.Index(i).Elem()).Int() < primaryField(items.Index(j).Elem()).Int()
+	})
+	return &memoryRows{items: items}
+}
+
+// memoryRows implements export.Rows and infra.RowIterator.
+type memoryRows struct {
+	items reflect.Value
+	index int
+	e     error
+}
+
+// Next .
+func (rows *memoryRows) Next() (interface{}, bool, error) {
+	if rows.e != nil {
+		return nil, false, rows.e
+	}
+	if !rows.items.IsValid() || rows.index >= rows.items.Len() {
+		return nil, false, nil
+	}
+	rows.index++
+	return rows.items.Index(rows.index - 1).Interface(), true, nil
+}
+
+// Close .
+func (rows *memoryRows) Close() error {
+	return nil
+}
+
+// MemoryGoods .
+type MemoryGoods struct {
+	Store *MemoryStore
+}
+
+// NewMemoryGoods .
+func NewMemoryGoods(store *MemoryStore) *MemoryGoods {
+	return &MemoryGoods{Store: store}
+}
+
+// Goods .
+func (repo *MemoryGoods) Goods(id int) (*po.Goods, error) {
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	result := &po.Goods{ID: id}
+	if e := repo.Store.Find(result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// AllGoods .
+func (repo *MemoryGoods) AllGoods() (infra.RowIterator, error) {
+	rows := newMemoryRows(repo.Store, &[]*po.Goods{}, "", nil)
+	if rows.e != nil {
+		return nil, rows.e
+	}
+	return rows, nil
+}
+
+// SaveGoods .
+func (repo *MemoryGoods) SaveGoods(goods *po.Goods, version int) (bool, error) {
+	goods.AddVersion(1)
+	affected, e := repo.Store.SaveIf(goods, "version", version)
+	return e == nil && affected > 0, e
+}
+
+// MemoryOrder .
+type MemoryOrder struct {
+	Store *MemoryStore
+}
+
+// NewMemoryOrder .
+func NewMemoryOrder(store *MemoryStore) *MemoryOrder {
+	return &MemoryOrder{Store: store}
+}
+
+// Order .
+func (repo *MemoryOrder) Order(id int) (*po.Order, error) {
+	if id <= 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	result := &po.Order{ID: id}
+	if e := repo.Store.Find(result); e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+// SaveOrder .
+func (repo *MemoryOrder) SaveOrder(order *po.Order, version int) (bool, error) {
+	order.AddVersion(1)
+	affected, e := repo.Store.SaveIf(order, "version", version)
+	return e == nil && affected > 0, e
+}
+
+// MemoryImport .
+type MemoryImport struct {
+	Store *MemoryStore
+	inTx  bool
+}
+
+// NewMemoryImport .
+func NewMemoryImport(store *MemoryStore) *MemoryImport {
+	return &MemoryImport{Store: store}
+}
+
+// Begin .
+func (repo *MemoryImport) Begin() error {
+	if repo.inTx {
+		return fmt.Errorf("transaction already started")
+	}
+	repo.Store.Begin()
+	repo.inTx = true
+	return nil
+}
+
+// Commit .
+func (repo *MemoryImport) Commit() error {
+	if !repo.inTx {
+		return fmt.Errorf("no transaction")
+	}
+	repo.inTx = false
+	repo.Store.Commit()
+	return nil
+}
+
+// Rollback .
+func (repo *MemoryImport) Rollback() error {
+	if !repo.inTx {
+		return fmt.Errorf("no transaction")
+	}
+	repo.inTx = false
+	repo.Store.Rollback()
+	return nil
+}
+
+// GoodsByNames .
+func (repo *MemoryImport) GoodsByNames(names []string) (map[string]*po.Goods, error) {
+	list := []*po.Goods{}
+	if e := repo.Store.FindListByWhere("name IN (?)", []interface{}{names}, &list); e != nil {
+		return nil, e
+	}
+	result := map[string]*po.Goods{}
+	for _, goods := range list {
+		result[goods.Name] = goods
+	}
+	return result, nil
+}
+
+// CreateGoods .
+func (repo *MemoryImport) CreateGoods(goods *po.Goods) error {
+	return repo.Store.Create(goods)
+}
+
+// SaveGoods .
+func (repo *MemoryImport) SaveGoods(goods *po.Goods) error {
+	_, e := repo.Store.Save(goods)
+	return e
+}
+
+// UsersByNames .
+func (repo *MemoryImport) UsersByNames(names []string) (map[string]*po.User, error) {
+	list := []*po.User{}
+	if e := repo.Store.FindListByWhere("name IN (?)", []interface{}{names}, &list); e != nil {
+		return nil, e
+	}
+	result := map[string]*po.User{}
+	for _, user := range list {
+		result[user.Name] = user
+	}
+	return result, nil
+}
+
+// CreateUser .
+func (repo *MemoryImport) CreateUser(user *po.User) error {
+	return repo.Store.Create(user)
+}
+
+// SaveUser .
+func (repo *MemoryImport) SaveUser(user *po.User) error {
+	_, e := repo.Store.Save(user)
+	return e
+}
@@ -0,0 +1,581 @@
+// Package repositorytest holds the test doubles of the repositories: the in-memory fakes of the domain
+// interfaces and the Store the contract checks read and write with, on the database or in memory.
+// It is imported by tests only.
+package repositorytest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/8treenet/dump/adapter/repository"
+	"github.com/jinzhu/gorm"
+)
+
+// Store is the po storage of the repositories, as done by the generated functions:
+// GORMStore on the database, MemoryStore in memory for the fakes of the tests.
+type Store interface {
+	// Find loads the last row, by primary key, matching the non-zero fields of result, gorm.ErrRecordNotFound when none.
+	Find(result interface{}) error
+	// FindList loads the rows matching the non-zero fields of query into results, a pointer to a po slice.
+	// pager orders and pages them, it may be nil.
+	FindList(query interface{}, results interface{}, pager *repository.Pager) error
+	// FindListByWhere loads the rows matching query, see MemoryStore.Where.
+	FindListByWhere(query string, args []interface{}, results interface{}) error
+	// Create inserts object, the zero primary key is set to the next id.
+	Create(object interface{}) error
+	// Save updates the columns of object.TakeChanges(), and returns the matched rows.
+	Save(object Changer) (int64, error)
+}
+
+// Changer is a po tracking its changed columns, e.g. po.Goods.
+type Changer interface {
+	TakeChanges() map[string]interface{}
+}
+
+// GORMStore .
+type GORMStore struct {
+	DB *gorm.DB
+}
+
+// Find .
+func (s *GORMStore) Find(result interface{}) error {
+	return s.DB.Where(result).Last(result).Error
+}
+
+// FindList .
+func (s *GORMStore) FindList(query interface{}, results interface{}, pager *repository.Pager) error {
+	db := s.DB.Where(query)
+	if pager == nil {
+		return db.Find(results).Error
+	}
+	return pager.Execute(db, results)
+}
+
+// FindListByWhere .
+func (s *GORMStore) FindListByWhere(query string, args []interface{}, results interface{}) error {
+	db := s.DB
+	if query != "" {
+		db = db.Where(query, args...)
+	}
+	return db.Find(results).Error
+}
+
+// Create inserts object with the generated create function of its model.
+func (s *GORMStore) Create(object interface{}) error {
+	_, e := (&repository.Store{DB: s.DB}).Create(object)
+	return e
+}
+
+// Save updates object with the generated save function of its model.
+func (s *GORMStore) Save(object Changer) (int64, error) {
+	return (&repository.Store{DB: s.DB}).Save(object)
+}
+
+// MemoryStore keeps the po rows in memory, it is safe for concurrent use.
+// Transactions are serialized: Begin waits for the running transaction to end.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	tables map[string]*memoryTable
+	txMu   sync.Mutex
+	backup map[string]*memoryTable
+}
+
+// memoryTable holds copies of the po structs, never the pointers given by the callers.
+type memoryTable struct {
+	rows   []reflect.Value
+	nextID int64
+}
+
+// NewMemoryStore .
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tables: map[string]*memoryTable{}}
+}
+
+// Begin starts a transaction, Commit or Rollback must follow.
+func (s *MemoryStore) Begin() {
+	s.txMu.Lock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backup = map[string]*memoryTable{}
+	for name, table := range s.tables {
+		s.backup[name] = table.clone()
+	}
+}
+
+// Commit .
+func (s *MemoryStore) Commit() {
+	s.mu.Lock()
+	s.backup = nil
+	s.mu.Unlock()
+	s.txMu.Unlock()
+}
+
+// Rollback restores the rows of Begin.
+func (s *MemoryStore) Rollback() {
+	s.mu.Lock()
+	s.tables, s.backup = s.backup, nil
+	s.mu.Unlock()
+	s.txMu.Unlock()
+}
+
+// Insert adds rows, e.g. s.Insert(&po.Goods{Name: "apple"}, &po.Goods{Name: "pear"}), like fixtures do.
+func (s *MemoryStore) Insert(objects ...interface{}) error {
+	for _, object := range objects {
+		if e := s.Create(object); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Find .
+func (s *MemoryStore) Find(result interface{}) error {
+	value := reflect.ValueOf(result).Elem()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rows := s.rows(value.Type())
+	for index := len(rows) - 1; index >= 0; index-- {
+		if matchFields(rows[index], value) {
+			value.Set(rows[index])
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// FindList .
+func (s *MemoryStore) FindList(query interface{}, results interface{}, pager *repository.Pager) error {
+	queryValue := reflect.Indirect(reflect.ValueOf(query))
+	return s.findList(results, pager, func(row reflect.Value) (bool, error) {
+		return matchFields(row, queryValue), nil
+	})
+}
+
+// FindListByWhere .
+func (s *MemoryStore) FindListByWhere(query string, args []interface{}, results interface{}) error {
+	match, e := s.Where(query, args...)
+	if e != nil {
+		return e
+	}
+	return s.findList(results, nil, match)
+}
+
+// Create .
+func (s *MemoryStore) Create(object interface{}) error {
+	value := reflect.ValueOf(object).Elem()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	table := s.table(value.Type())
+	primary := primaryField(value)
+	if !primary.IsValid() {
+		return fmt.Errorf("%s has no primary key", value.Type())
+	}
+	id := primary.Int()
+	if id == 0 {
+		id = table.nextID + 1
+		primary.SetInt(id)
+	}
+	for _, row := range table.rows {
+		if primaryField(row).Int() == id {
+			return fmt.Errorf("duplicate primary key %d of %s", id, value.Type().Name())
+		}
+	}
+	if id > table.nextID {
+		table.nextID = id
+	}
+	table.rows = append(table.rows, copyRow(value))
+	return nil
+}
+
+// Save .
+func (s *MemoryStore) Save(object Changer) (int64, error) {
+	return s.save(object, "", nil)
+}
+
+// SaveIf saves object only if the column of its row still equals value, e.g. the version the object was read at.
+func (s *MemoryStore) SaveIf(object Changer, column string, value interface{}) (int64, error) {
+	return s.save(object, column, value)
+}
+
+func (s *MemoryStore) save(object Changer, conditionColumn string, conditionValue interface{}) (int64, error) {
+	value := reflect.ValueOf(object).Elem()
+	changes := object.TakeChanges()
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	columns := columnFields(value.Type())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := primaryField(value).Int()
+	for _, row := range s.table(value.Type()).rows {
+		if primaryField(row).Int() != id {
+			continue
+		}
+		if conditionColumn != "" {
+			field, ok := columns[conditionColumn]
+			if !ok {
+				return 0, fmt.Errorf("unknown column '%s' of %s", conditionColumn, value.Type().Name())
+			}
+			if result, e := compareValues(row.FieldByIndex(field.Index), conditionValue); e != nil || result != 0 {
+				return 0, e
+			}
+		}
+		//先校验全部列, 失败时不留下部分修改
+		updated := copyRow(row)
+		for column, change := range changes {
+			field, ok := columns[column]
+			if !ok {
+				return 0, fmt.Errorf("unknown column '%s' of %s", column, value.Type().Name())
+			}
+			if e := setColumn(updated.FieldByIndex(field.Index), column, change); e != nil {
+				return 0, e
+			}
+		}
+		row.Set(updated)
+		return 1, nil
+	}
+	return 0, nil
+}
+
+var addition = regexp.MustCompile(`^\s*(\w+)\s*\+\s*\?\s*$`)
+
+// setColumn sets a change of TakeChanges, a value or the "column + ?" expression of the generated Add methods.
+func setColumn(target reflect.Value, column string, change interface{}) error {
+	expr, ok := change.(*gorm.SqlExpr)
+	if !ok {
+		if change == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		target.Set(reflect.ValueOf(change).Convert(target.Type()))
+		return nil
+	}
+	//gorm.SqlExpr的字段未导出, 通过反射读取
+	fields := reflect.ValueOf(expr).Elem()
+	text, args := fields.FieldByName("expr").String(), fields.FieldByName("args")
+	match := addition.FindStringSubmatch(text)
+	if match == nil || match[1] != column || args.Len() != 1 {
+		return fmt.Errorf("unsupported expression '%s' of column '%s'", text, column)
+	}
+	arg := args.Index(0).Elem()
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target.SetInt(target.Int() + arg.Int())
+	case reflect.Float32, reflect.Float64:
+		target.SetFloat(target.Float() + arg.Float())
+	default:
+		return fmt.Errorf("unsupported expression '%s' of column '%s'", text, column)
+	}
+	return nil
+}
+
+func (s *MemoryStore) findList(results interface{}, pager *repository.Pager, match func(reflect.Value) (bool, error)) error {
+	slice := reflect.ValueOf(results).Elem()
+	itemType := slice.Type().Elem()
+	structType := itemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	s.mu.RLock()
+	matched := []reflect.Value{}
+	for _, row := range s.rows(structType) {
+		ok, e := match(row)
+		if e != nil {
+			s.mu.RUnlock()
+			return e
+		}
+		if ok {
+			matched = append(matched, copyRow(row))
+		}
+	}
+	s.mu.RUnlock()
+
+	if pager != nil {
+		var e error
+		if matched, e = applyPager(pager, structType, matched); e != nil {
+			return e
+		}
+	}
+	result := reflect.MakeSlice(slice.Type(), 0, len(matched))
+	for _, row := range matched {
+		if itemType.Kind() == reflect.Ptr {
+			result = reflect.Append(result, row.Addr())
+		} else {
+			result = reflect.Append(result, row)
+		}
+	}
+	slice.Set(result)
+	return nil
+}
+
+// rows must be called with mu held.
+func (s *MemoryStore) rows(t reflect.Type) []reflect.Value {
+	if table, ok := s.tables[tableName(t)]; ok {
+		return table.rows
+	}
+	return nil
+}
+
+// table must be called with mu locked.
+func (s *MemoryStore) table(t reflect.Type) *memoryTable {
+	name := tableName(t)
+	table, ok := s.tables[name]
+	if !ok {
+		table = &memoryTable{}
+		s.tables[name] = table
+	}
+	return table
+}
+
+func (t *memoryTable) clone() *memoryTable {
+	result := &memoryTable{nextID: t.nextID}
+	for _, row := range t.rows {
+		result.rows = append(result.rows, copyRow(row))
+	}
+	return result
+}
+
+// applyPager orders and pages rows as Pager.Execute does, and sets the totals.
+func applyPager(p *repository.Pager, t reflect.Type, rows []reflect.Value) ([]reflect.Value, error) {
+	columns := columnFields(t)
+	fields, orders := p.Orders()
+	for _, name := range fields {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column '%s' of %s", name, t.Name())
+		}
+	}
+	if len(fields) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for index, name := range fields {
+				field := columns[name]
+				result, _ := compareValues(rows[i].FieldByIndex(field.Index), rows[j].FieldByIndex(field.Index).Interface())
+				if result == 0 {
+					continue
+				}
+				if strings.EqualFold(orders[index], "desc") {
+					return result > 0
+				}
+				return result < 0
+			}
+			return false
+		})
+	}
+	if p.Page() == 0 || p.PageSize() == 0 {
+		return rows, nil
+	}
+
+	if count := len(rows); count != 0 {
+		p.SetTotal(count)
+	}
+	start := (p.Page() - 1) * p.PageSize()
+	if start >= len(rows) {
+		return nil, nil
+	}
+	end := start + p.PageSize()
+	if end > len(rows) {
+		end = len(rows)
+	}
+	return rows[start:end], nil
+}
+
+var (
+	condition   = regexp.MustCompile(`(?i)^\s*(\w+)\s*(=|<>|!=|<=|>=|<|>|LIKE|IN)\s*(\(\s*\?\s*\)|\?)\s*$`)
+	conjunction = regexp.MustCompile(`(?i)\s+AND\s+`)
+)
+
+// Where returns a matcher of the SQL conditions the repositories use: "column op ?" joined by AND,
+// op is =, <>, !=, <, <=, >, >=, LIKE or IN, the IN argument is a slice.
+func (s *MemoryStore) Where(query string, args ...interface{}) (func(row reflect.Value) (bool, error), error) {
+	type clause struct {
+		column, op string
+		arg        interface{}
+	}
+	clauses := []clause{}
+	if strings.TrimSpace(query) != "" {
+		parts := conjunction.Split(query, -1)
+		if len(parts) != len(args) {
+			return nil, fmt.Errorf("query '%s' has %d conditions, got %d arguments", query, len(parts), len(args))
+		}
+		for index, part := range parts {
+			match := condition.FindStringSubmatch(part)
+			if match == nil {
+				return nil, fmt.Errorf("unsupported condition '%s'", part)
+			}
+			clauses = append(clauses, clause{column: match[1], op: strings.ToUpper(match[2]), arg: args[index]})
+		}
+	}
+
+	return func(row reflect.Value) (bool, error) {
+		columns := columnFields(row.Type())
+		for _, item := range clauses {
+			field, ok := columns[item.column]
+			if !ok {
+				return false, fmt.Errorf("unknown column '%s' of %s", item.column, row.Type().Name())
+			}
+			ok, e := matchCondition(row.FieldByIndex(field.Index), item.op, item.arg)
+			if e != nil || !ok {
+				return false, e
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+func matchCondition(value reflect.Value, op string, arg interface{}) (bool, error) {
+	switch op {
+	case "LIKE":
+		pattern := regexp.QuoteMeta(fmt.Sprint(arg))
+		pattern = strings.NewReplacer("%", ".*", "_", ".").Replace(pattern)
+		return regexp.MatchString("(?is)^"+pattern+"$", fmt.Sprint(value.Interface()))
+	case "IN":
+		list := reflect.ValueOf(arg)
+		if list.Kind() != reflect.Slice {
+			return false, fmt.Errorf("IN expects a slice, got %T", arg)
+		}
+		for index := 0; index < list.Len(); index++ {
+			if result, e := compareValues(value, list.Index(index).Interface()); e != nil || result == 0 {
+				return e == nil, e
+			}
+		}
+		return false, nil
+	}
+	result, e := compareValues(value, arg)
+	if e != nil {
+		return false, e
+	}
+	switch op {
+	case "=":
+		return result == 0, nil
+	case "<>", "!=":
+		return result != 0, nil
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	default:
+		return result >= 0, nil
+	}
+}
+
+// timeLayouts are the layouts of the time arguments given as strings, e.g. by the export filters.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// compareValues compares a field with an argument, converted to the type of the field as the database would.
+func compareValues(value reflect.Value, arg interface{}) (int, error) {
+	if t, ok := value.Interface().(time.Time); ok {
+		other, ok := arg.(time.Time)
+		if !ok {
+			text := fmt.Sprint(arg)
+			var e error
+			for _, layout := range timeLayouts {
+				if other, e = time.ParseInLocation(layout, text, time.Local); e == nil {
+					break
+				}
+			}
+			if e != nil {
+				return 0, fmt.Errorf("'%s' is not a time", text)
+			}
+		}
+		switch {
+		case t.Before(other):
+			return -1, nil
+		case t.After(other):
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		left, _ := strconv.ParseFloat(fmt.Sprint(value.Interface()), 64)
+		right, e := strconv.ParseFloat(fmt.Sprint(arg), 64)
+		if e != nil {
+			return 0, fmt.Errorf("'%v' is not a number", arg)
+		}
+		switch {
+		case left < right:
+			return -1, nil
+		case left > right:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return strings.Compare(fmt.Sprint(value.Interface()), fmt.Sprint(arg)), nil
+}
+
+// matchFields reports whether row has the non-zero column values of query, as gorm's Where(struct).
+func matchFields(row, query reflect.Value) bool {
+	for _, field := range columnFields(query.Type()) {
+		value := query.FieldByIndex(field.Index)
+		if isZero(value) {
+			continue
+		}
+		if !reflect.DeepEqual(row.FieldByIndex(field.Index).Interface(), value.Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(value reflect.Value) bool {
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+// columnFields returns the column fields of a po by column name, from the gorm tags.
+func columnFields(t reflect.Type) map[string]reflect.StructField {
+	result := map[string]reflect.StructField{}
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if field.PkgPath != "" || field.Tag.Get("gorm") == "-" {
+			continue
+		}
+		result[columnName(field)] = field
+	}
+	return result
+}
+
+func columnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(strings.ToLower(setting), "column:") {
+			return setting[len("column:"):]
+		}
+	}
+	return gorm.ToColumnName(field.Name)
+}
+
+func primaryField(value reflect.Value) reflect.Value {
+	t := value.Type()
+	for index := 0; index < t.NumField(); index++ {
+		if strings.Contains(strings.ToLower(t.Field(index).Tag.Get("gorm")), "primary_key") {
+			return value.Field(index)
+		}
+	}
+	return value.FieldByName("ID")
+}
+
+// copyRow returns an addressable copy of a po struct, without its unexported change tracking.
+func copyRow(value reflect.Value) reflect.Value {
+	result := reflect.New(value.Type()).Elem()
+	for _, field := range columnFields(value.Type()) {
+		result.FieldByIndex(field.Index).Set(value.FieldByIndex(field.Index))
+	}
+	return result
+}
+
+func tableName(t reflect.Type) string {
+	if tabler, ok := reflect.New(t).Interface().(interface{ TableName() string }); ok {
+		return tabler.TableName()
+	}
+	return gorm.ToTableName(t.Name())
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/8treenet/dump/infra/masking"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+// RequestIDHeader is the header of the request id, the one of the Trace middleware.
+const RequestIDHeader = "x-request-id"
+
+var (
+	slowQuery  int64 // 纳秒, 0 关闭慢查询日志
+	sqlComment int32
+)
+
+// SetSlowQuery sets the duration above which a statement is logged, 0 disables the slow query log.
+// comment appends the request id to the SQL of the ORM helpers, e.g. /* x-request-id=abc */,
+// so that the queries seen by the database can be correlated to requests.
+func SetSlowQuery(threshold time.Duration, comment bool) {
+	atomic.StoreInt64(&slowQuery, int64(threshold))
+	var value int32
+	if comment {
+		value = 1
+	}
+	atomic.StoreInt32(&sqlComment, value)
+}
+
+const (
+	ormCallKey   = "repository:orm_call"
+	slowStartKey = "repository:slow_start"
+)
+
+// ormCall is the helper running a statement, requestID is read when the helper is called:
+// an iterator can outlive the request, and its context is then reused by another one.
+type ormCall struct {
+	model, method, requestID string
+	worker                   freedom.Worker
+	ctx                      context.Context // 请求的context, 带有当前span
+}
+
+// InstallSlowLog registers the callbacks timing the statements of db, for the primary and every replica.
+func InstallSlowLog(db *gorm.DB) {
+	callbacks := db.Callback()
+	callbacks.Create().Before("gorm:create").Register("repository:slow_start", slowStart)
+	callbacks.Create().After("gorm:create").Register("repository:slow_log", slowLog)
+	callbacks.Query().Before("gorm:query").Register("repository:slow_start", slowStart)
+	callbacks.Query().After("gorm:query").Register("repository:slow_log", slowLog)
+	callbacks.Update().Before("gorm:update").Register("repository:slow_start", slowStart)
+	callbacks.Update().After("gorm:update").Register("repository:slow_log", slowLog)
+	callbacks.Delete().Before("gorm:delete").Register("repository:slow_start", slowStart)
+	callbacks.Delete().After("gorm:delete").Register("repository:slow_log", slowLog)
+	callbacks.RowQuery().Before("gorm:row_query").Register("repository:slow_start", slowStart)
+	callbacks.RowQuery().After("gorm:row_query").Register("repository:slow_log", slowLog)
+}
+
+// ormDB tags db with the model and method of a helper for the slow query log, and adds the SQL comment.
+func ormDB(repo GORMRepository, db *gorm.DB, model, method string) *gorm.DB {
+	worker := repo.GetWorker()
+	id := requestID(worker)
+	call := &ormCall{model: model, method: method, requestID: id, worker: worker, ctx: requestContext(worker)}
+	db = db.Set(ormCallKey, call)
+	if atomic.LoadInt32(&sqlComment) == 0 || id == "" {
+		return db
+	}
+	comment := fmt.Sprintf("/* %s=%s */", RequestIDHeader, id)
+	for _, key := range []string{"gorm:query_option", "gorm:insert_option", "gorm:update_option", "gorm:delete_option"} {
+		//保留已有的选项, 例如 FOR UPDATE
+		if option, ok := db.Get(key); ok {
+			db = db.Set(key, fmt.Sprint(option)+" "+comment)
+			continue
+		}
+		db = db.Set(key, comment)
+	}
+	return db
+}
+
+var unsafeRequestID = regexp.MustCompile(`[^A-Za-z0-9._:-]`)
+
+// requestID returns the request id of the worker, reduced to characters that can't end a SQL comment.
+func requestID(worker freedom.Worker) string {
+	if worker == nil || worker.IrisContext() == nil {
+		return ""
+	}
+	id := unsafeRequestID.ReplaceAllString(worker.IrisContext().GetHeader(RequestIDHeader), "")
+	if len(id) > 64 {
+		id = id[:64]
+	}
+	return id
+}
+
+func slowStart(scope *gorm.Scope) {
+	if atomic.LoadInt64(&slowQuery) > 0 {
+		scope.InstanceSet(slowStartKey, time.Now())
+	}
+}
+
+func slowLog(scope *gorm.Scope) {
+	threshold := time.Duration(atomic.LoadInt64(&slowQuery))
+	start, ok := scope.InstanceGet(slowStartKey)
+	if threshold <= 0 || !ok {
+		return
+	}
+	elapsed := time.Since(start.(time.Time))
+	if elapsed < threshold {
+		return
+	}
+
+	call := ormCall{method: "-"}
+	if value, ok := scope.Get(ormCallKey); ok {
+		call = *value.(*ormCall)
+	}
+	if call.model == "" {
+		call.model = scope.TableName()
+	}
+	line := fmt.Sprintf("Slow query, model: %s, method: %s, elapsed: %s, rows affected: %d, request id: %s, sql: %s, args: %v",
+		call.model, call.method, elapsed, scope.DB().RowsAffected, call.requestID, scope.SQL, maskVars(scope))
+	//请求的logger会带上Trace中间件的请求id
+	if call.worker != nil {
+		call.worker.Logger().Warn(line)
+		return
+	}
+	freedom.Logger().Warn(line)
+}
+
+var (
+	placeholder    = regexp.MustCompile(`\?|\$\d+`)
+	insertColumns  = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*VALUES`)
+	placeholderCol = regexp.MustCompile(`(?is)["` + "`" + `]?(\w+)["` + "`" + `]?\s*(?:=|<>|!=|<=|>=|<|>|\bLIKE|\bIN)\s*\(?\s*$`)
+)
+
+// maskVars masks the arguments of the sensitive columns of the model, see infra/masking.
+// The column of an argument is the one compared to its placeholder, or its position in an INSERT.
+func maskVars(scope *gorm.Scope) []interface{} {
+	result := append([]interface{}{}, scope.SQLVars...)
+	modelType := scope.GetModelStruct().ModelType
+	if modelType == nil {
+		return result
+	}
+	policy := masking.Policy(reflect.New(modelType).Interface())
+	if len(policy) == 0 {
+		return result
+	}
+
+	columns := placeholderColumns(scope.SQL)
+	for index := range result {
+		if index >= len(columns) {
+			break
+		}
+		action, ok := policy[columns[index]]
+		if !ok {
+			continue
+		}
+		if action == masking.Drop {
+			action = masking.Mask
+		}
+		result[index], _ = masking.Apply(action, columns[index], result[index])
+	}
+	return result
+}
+
+// placeholderColumns returns the column of every placeholder of sql, "" when unknown.
+func placeholderColumns(sql string) []string {
+	result := []string{}
+	insert := []string{}
+	if match := insertColumns.FindStringSubmatch(sql); match != nil {
+		for _, column := range strings.Split(match[1], ",") {
+			insert = append(insert, strings.Trim(strings.TrimSpace(column), "\"`"))
+		}
+	}
+	for index, position := range placeholder.FindAllStringIndex(sql, -1) {
+		if index < len(insert) {
+			result = append(result, insert[index])
+			continue
+		}
+		column := ""
+		if match := placeholderCol.FindStringSubmatch(sql[:position[0]]); match != nil {
+			column = match[1]
+		} else if index > 0 && strings.HasSuffix(strings.TrimSpace(sql[:position[0]]), ",") {
+			column = result[index-1] //IN (?,?) 展开后的参数
+		}
+		result = append(result, column)
+	}
+	return result
+}
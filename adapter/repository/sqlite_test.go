@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/8treenet/dump/adapter/repository"
+	"github.com/8treenet/dump/adapter/repository/contract"
+	"github.com/8treenet/dump/adapter/repository/repositorytest"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra/apptest"
+	_ "github.com/8treenet/dump/server/migrations"
+)
+
+// TestSQLite runs the store checks on the baseline schema migrated into SQLite.
+func TestSQLite(t *testing.T) {
+	db, err := apptest.NewDB("../../server/migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := contract.Store(&repositorytest.GORMStore{DB: db}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPagerOrder checks that OrderFor quotes the columns for the database, Order keeps the mysql quoting.
+func TestPagerOrder(t *testing.T) {
+	db, err := apptest.NewDB("../../server/migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	pager := repository.NewDescPager("price", "id").SetPage(1, 10)
+	if order := pager.OrderFor(db.Dialect()); order != `"price" desc,"id" desc` {
+		t.Fatalf("OrderFor: unexpected %v", order)
+	}
+	if order := pager.Order(); order != "`price` desc,`id` desc" {
+		t.Fatalf("Order: unexpected %v", order)
+	}
+	list := []*po.Goods{}
+	if err := pager.Execute(db, &list); err != nil {
+		t.Fatal(err)
+	}
+}
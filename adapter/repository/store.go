@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+)
+
+// Store creates and saves po objects of any model with their generated functions, outside a request.
+// repositorytest.GORMStore writes with it, so that the contract checks run the generated code.
+type Store struct {
+	DB *gorm.DB
+}
+
+// Create inserts object, a pointer to a po, with its create function.
+func (s *Store) Create(object interface{}) (int64, error) {
+	return create(s, object)
+}
+
+// Save updates the changed columns of object, a pointer to a po, with its save function.
+func (s *Store) Save(object interface{}) (int64, error) {
+	return save(s, object)
+}
+
+// GetWorker returns nil, a Store isn't bound to a request.
+func (s *Store) GetWorker() freedom.Worker {
+	return nil
+}
+
+// db .
+func (s *Store) db() *gorm.DB {
+	return s.DB.New()
+}
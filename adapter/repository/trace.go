@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/8treenet/dump/infra/tracing"
+	"github.com/8treenet/freedom"
+	"github.com/go-redis/redis"
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceSpanKey = "repository:trace_span"
+
+// InstallTracing registers the callbacks recording a span per statement of db, child of the span of the request.
+// The span is named after the model and method of the helper, e.g. Goods.findGoodsListByWhere.
+func InstallTracing(db *gorm.DB) {
+	callbacks := db.Callback()
+	callbacks.Create().Before("gorm:create").Register("repository:trace_start", traceStart)
+	callbacks.Create().After("gorm:create").Register("repository:trace_end", traceEnd)
+	callbacks.Query().Before("gorm:query").Register("repository:trace_start", traceStart)
+	callbacks.Query().After("gorm:query").Register("repository:trace_end", traceEnd)
+	callbacks.Update().Before("gorm:update").Register("repository:trace_start", traceStart)
+	callbacks.Update().After("gorm:update").Register("repository:trace_end", traceEnd)
+	callbacks.Delete().Before("gorm:delete").Register("repository:trace_start", traceStart)
+	callbacks.Delete().After("gorm:delete").Register("repository:trace_end", traceEnd)
+	callbacks.RowQuery().Before("gorm:row_query").Register("repository:trace_start", traceStart)
+	callbacks.RowQuery().After("gorm:row_query").Register("repository:trace_end", traceEnd)
+}
+
+// RedisRepository .
+type RedisRepository interface {
+	Redis() redis.Cmdable
+	GetWorker() freedom.Worker
+}
+
+// Redis returns the Redis client of the request of repo, its commands record spans child of the span of the request,
+// e.g. Redis(repo).Get(key).
+func Redis(repo RedisRepository) redis.Cmdable {
+	return tracing.Redis(requestContext(repo.GetWorker()), repo.Redis())
+}
+
+// requestContext returns the context of the request of worker, with the span of the request,
+// context.Background() without a request, e.g. for the detached repositories.
+func requestContext(worker freedom.Worker) context.Context {
+	if worker == nil || worker.IrisContext() == nil {
+		return context.Background()
+	}
+	return worker.IrisContext().Request().Context()
+}
+
+func traceStart(scope *gorm.Scope) {
+	if !tracing.Enabled() {
+		return
+	}
+	call := ormCall{method: "-", ctx: context.Background()}
+	if value, ok := scope.Get(ormCallKey); ok {
+		call = *value.(*ormCall)
+	}
+	if call.model == "" {
+		call.model = scope.TableName()
+	}
+	_, span := tracing.Start(call.ctx, call.model+"."+call.method, trace.SpanKindClient,
+		attribute.String("db.system", scope.Dialect().GetName()),
+		attribute.String("db.sql.table", scope.TableName()),
+		attribute.String("repository.model", call.model),
+		attribute.String("repository.method", call.method),
+	)
+	scope.InstanceSet(traceSpanKey, span)
+}
+
+func traceEnd(scope *gorm.Scope) {
+	value, ok := scope.InstanceGet(traceSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	//SQL只含占位符, 参数不会进入span
+	span.SetAttributes(
+		attribute.String("db.statement", scope.SQL),
+		attribute.Int64("db.rows_affected", scope.DB().RowsAffected),
+	)
+	if e := scope.DB().Error; e != gorm.ErrRecordNotFound {
+		tracing.SetError(span, e)
+	}
+	span.End()
+}
@@ -2,7 +2,6 @@
 package domain
 
 import (
-	"github.com/8treenet/dump/adapter/repository"
 	"github.com/8treenet/freedom"
 )
 
@@ -21,7 +20,7 @@ func init() {
 // Default .
 type Default struct {
 	Worker  freedom.Worker
-	DefRepo *repository.Default
+	DefRepo DefaultRepository
 }
 
 // RemoteInfo .
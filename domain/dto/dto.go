@@ -1,2 +1,47 @@
 //Package dto generated by 'freedom new-project github.com/8treenet/dump'
 package dto
+
+// GoodsImport is an imported goods row, name is the key of upserts.
+type GoodsImport struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Price int    `json:"price" validate:"gte=0"`
+	Stock int    `json:"stock" validate:"gte=0"`
+	Tag   string `json:"tag" validate:"max=255"`
+}
+
+// UserImport is an imported user row, name is the key of upserts.
+type UserImport struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	Money    int    `json:"money" validate:"gte=0"`
+	Password string `json:"password" validate:"required,min=6,max=255"`
+}
+
+// ImportSummary is the result of an import.
+type ImportSummary struct {
+	DryRun   bool             `json:"dryRun"`
+	Rows     int              `json:"rows"`
+	Inserted int              `json:"inserted"`
+	Updated  int              `json:"updated"`
+	Skipped  int              `json:"skipped"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ImportRowError .
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// GoodsUpdate is a partial update of goods, absent fields are unchanged.
+type GoodsUpdate struct {
+	Name  *string `json:"name" validate:"omitempty,max=255"`
+	Price *int    `json:"price" validate:"omitempty,gte=0"`
+	Stock *int    `json:"stock" validate:"omitempty,gte=0"`
+	Tag   *string `json:"tag" validate:"omitempty,max=255"`
+}
+
+// OrderUpdate .
+type OrderUpdate struct {
+	Status string `json:"status" validate:"required,max=255"`
+}
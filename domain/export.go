@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra/export"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindService(func() *Export {
+			return &Export{}
+		})
+		initiator.InjectController(func(ctx freedom.Context) (service *Export) {
+			initiator.GetService(ctx, &service)
+			return
+		})
+	})
+}
+
+// ExportBatchSize is the number of rows read per query by the exports.
+const ExportBatchSize = 500
+
+// exportDataset is an exportable po list and its filters, query parameter to SQL condition.
+type exportDataset struct {
+	title   string
+	object  interface{}
+	filters map[string]string
+	rows    func(repo ExportRepository, query string, args ...interface{}) export.Rows
+}
+
+var exportDatasets = map[string]exportDataset{
+	"orders": {
+		title:  "订单",
+		object: &po.Order{},
+		filters: map[string]string{
+			"orderNo":     "order_no = ?",
+			"userID":      "user_id = ?",
+			"status":      "status = ?",
+			"createdFrom": "created >= ?",
+			"createdTo":   "created < ?",
+		},
+		rows: func(repo ExportRepository, query string, args ...interface{}) export.Rows {
+			return repo.Orders(ExportBatchSize, query, args...)
+		},
+	},
+	"order-details": {
+		title:  "订单明细",
+		object: &po.OrderDetail{},
+		filters: map[string]string{
+			"orderNo":     "order_no = ?",
+			"goodsID":     "goods_id = ?",
+			"createdFrom": "created >= ?",
+			"createdTo":   "created < ?",
+		},
+		rows: func(repo ExportRepository, query string, args ...interface{}) export.Rows {
+			return repo.OrderDetails(ExportBatchSize, query, args...)
+		},
+	},
+	"deliveries": {
+		title:  "发货",
+		object: &po.Delivery{},
+		filters: map[string]string{
+			"orderNo":        "order_no = ?",
+			"adminID":        "admin_id = ?",
+			"trackingNumber": "tracking_number = ?",
+			"createdFrom":    "created >= ?",
+			"createdTo":      "created < ?",
+		},
+		rows: func(repo ExportRepository, query string, args ...interface{}) export.Rows {
+			return repo.Deliveries(ExportBatchSize, query, args...)
+		},
+	},
+	"goods": {
+		title:  "商品库存",
+		object: &po.Goods{},
+		filters: map[string]string{
+			"tag":        "tag = ?",
+			"name":       "name LIKE ?",
+			"stockBelow": "stock < ?",
+		},
+		rows: func(repo ExportRepository, query string, args ...interface{}) export.Rows {
+			return repo.Goods(ExportBatchSize, query, args...)
+		},
+	},
+}
+
+// Export builds the admin reporting exports.
+type Export struct {
+	Worker     freedom.Worker
+	ExportRepo ExportRepository
+}
+
+// Columns returns the columns of dataset, all of them when selected is empty.
+func (s *Export) Columns(dataset string, selected []string) ([]export.Column, error) {
+	set, ok := exportDatasets[dataset]
+	if !ok {
+		return nil, fmt.Errorf("unknown export '%s'", dataset)
+	}
+	return export.Columns(set.object, selected...)
+}
+
+// Rows returns the rows of dataset matching filters, filters without a value are ignored.
+func (s *Export) Rows(dataset string, filters map[string]string) (export.Rows, error) {
+	return s.rows(s.ExportRepo, dataset, filters)
+}
+
+// BackgroundRows is Rows for an export running in the background, the rows are read after the request is done.
+func (s *Export) BackgroundRows(dataset string, filters map[string]string) (export.Rows, error) {
+	return s.rows(s.ExportRepo.Detached(), dataset, filters)
+}
+
+func (s *Export) rows(repo ExportRepository, dataset string, filters map[string]string) (export.Rows, error) {
+	set, ok := exportDatasets[dataset]
+	if !ok {
+		return nil, fmt.Errorf("unknown export '%s'", dataset)
+	}
+	keys := []string{}
+	for key, value := range filters {
+		if _, ok := set.filters[key]; ok && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	conditions := []string{}
+	args := []interface{}{}
+	for _, key := range keys {
+		value := filters[key]
+		if strings.Contains(set.filters[key], "LIKE") {
+			value = "%" + value + "%"
+		}
+		conditions = append(conditions, set.filters[key])
+		args = append(args, value)
+	}
+	return set.rows(repo, strings.Join(conditions, " AND "), args...), nil
+}
+
+// FileName returns the download name of dataset, e.g. 订单-20200102150405.xlsx.
+func (s *Export) FileName(dataset, format string) string {
+	return fmt.Sprintf("%s-%s.%s", exportDatasets[dataset].title, time.Now().Format("20060102150405"), format)
+}
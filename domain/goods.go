@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindService(func() *Goods {
+			return &Goods{}
+		})
+		initiator.InjectController(func(ctx freedom.Context) (service *Goods) {
+			initiator.GetService(ctx, &service)
+			return
+		})
+	})
+}
+
+// Precondition checks the client's copy of a resource against its current version,
+// e.g. infra.Request.CheckPrecondition with the If-Match header.
+type Precondition func(etag string, lastModified time.Time) error
+
+// Goods reads and updates goods with optimistic concurrency, see Precondition.
+type Goods struct {
+	Worker    freedom.Worker
+	GoodsRepo GoodsRepository
+}
+
+// Get .
+func (s *Goods) Get(id int) (*po.Goods, error) {
+	return s.GoodsRepo.Goods(id)
+}
+
+// List iterates all the goods, the rows are read while the response is written.
+func (s *Goods) List() (infra.RowIterator, error) {
+	return s.GoodsRepo.AllGoods()
+}
+
+// Update applies update when check accepts the current version of the goods,
+// infra.ErrPreconditionFailed is returned when the goods changed meanwhile.
+func (s *Goods) Update(id int, update dto.GoodsUpdate, check Precondition) (*po.Goods, error) {
+	goods, e := s.GoodsRepo.Goods(id)
+	if e != nil {
+		return nil, e
+	}
+	version := goods.Version
+	if e := check(infra.VersionETag(version), goods.Updated); e != nil {
+		return nil, e
+	}
+
+	if update.Name != nil {
+		goods.SetName(*update.Name)
+	}
+	if update.Price != nil {
+		goods.SetPrice(*update.Price)
+	}
+	if update.Stock != nil {
+		goods.SetStock(*update.Stock)
+	}
+	if update.Tag != nil {
+		goods.SetTag(*update.Tag)
+	}
+	goods.SetUpdated(time.Now())
+	//读取与写入之间被其他请求修改时同样返回412
+	saved, e := s.GoodsRepo.SaveGoods(goods, version)
+	if e != nil {
+		return nil, e
+	}
+	if !saved {
+		return nil, infra.ErrPreconditionFailed
+	}
+	return goods, nil
+}
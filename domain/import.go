@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/importer"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindService(func() *Import {
+			return &Import{}
+		})
+		initiator.InjectController(func(ctx freedom.Context) (service *Import) {
+			initiator.GetService(ctx, &service)
+			return
+		})
+	})
+}
+
+const (
+	// ImportBatchSize is the default number of rows committed per transaction.
+	ImportBatchSize = 500
+	// ImportMaxErrors bounds the row errors listed in a summary, Failed still counts all of them.
+	ImportMaxErrors = 1000
+)
+
+// ImportOptions .
+type ImportOptions struct {
+	Format string // csv or jsonl
+	// DryRun validates and writes every batch in one transaction, then rolls it back.
+	DryRun bool
+	// Upsert updates the existing rows with the same key, otherwise they are skipped.
+	Upsert    bool
+	BatchSize int
+}
+
+// Import bulk imports goods and users.
+type Import struct {
+	Worker     freedom.Worker
+	ImportRepo ImportRepository
+}
+
+// importRow is a valid row waiting for its batch.
+type importRow struct {
+	number int
+	row    interface{}
+}
+
+// batchCounts are the counts of a batch, added to the summary once the batch is committed.
+type batchCounts struct {
+	inserted, updated, skipped int
+}
+
+// Goods imports dto.GoodsImport rows, the key is the name.
+func (s *Import) Goods(r io.Reader, opt ImportOptions) (*dto.ImportSummary, error) {
+	newRow := func() interface{} { return &dto.GoodsImport{} }
+	return s.run(r, opt, newRow, func(rows []importRow) (counts batchCounts, e error) {
+		names := []string{}
+		for _, item := range rows {
+			names = append(names, item.row.(*dto.GoodsImport).Name)
+		}
+		existing, e := s.ImportRepo.GoodsByNames(names)
+		if e != nil {
+			return
+		}
+		now := time.Now()
+		for _, item := range rows {
+			row := item.row.(*dto.GoodsImport)
+			goods, ok := existing[row.Name]
+			if !ok {
+				goods = &po.Goods{Name: row.Name, Price: row.Price, Stock: row.Stock, Tag: row.Tag, Created: now, Updated: now}
+				if e = s.ImportRepo.CreateGoods(goods); e != nil {
+					return
+				}
+				existing[row.Name] = goods
+				counts.inserted++
+				continue
+			}
+			if !opt.Upsert || (goods.Price == row.Price && goods.Stock == row.Stock && goods.Tag == row.Tag) {
+				counts.skipped++
+				continue
+			}
+			goods.SetPrice(row.Price)
+			goods.SetStock(row.Stock)
+			goods.SetTag(row.Tag)
+			goods.SetUpdated(now)
+			if e = s.ImportRepo.SaveGoods(goods); e != nil {
+				return
+			}
+			counts.updated++
+		}
+		return
+	})
+}
+
+// Users imports dto.UserImport rows, the key is the name.
+func (s *Import) Users(r io.Reader, opt ImportOptions) (*dto.ImportSummary, error) {
+	newRow := func() interface{} { return &dto.UserImport{} }
+	return s.run(r, opt, newRow, func(rows []importRow) (counts batchCounts, e error) {
+		names := []string{}
+		for _, item := range rows {
+			names = append(names, item.row.(*dto.UserImport).Name)
+		}
+		existing, e := s.ImportRepo.UsersByNames(names)
+		if e != nil {
+			return
+		}
+		now := time.Now()
+		for _, item := range rows {
+			row := item.row.(*dto.UserImport)
+			user, ok := existing[row.Name]
+			if !ok {
+				user = &po.User{Name: row.Name, Money: row.Money, Password: row.Password, Created: now, Updated: now}
+				if e = s.ImportRepo.CreateUser(user); e != nil {
+					return
+				}
+				existing[row.Name] = user
+				counts.inserted++
+				continue
+			}
+			if !opt.Upsert || (user.Money == row.Money && user.Password == row.Password) {
+				counts.skipped++
+				continue
+			}
+			user.SetMoney(row.Money)
+			user.SetPassword(row.Password)
+			user.SetUpdated(now)
+			if e = s.ImportRepo.SaveUser(user); e != nil {
+				return
+			}
+			counts.updated++
+		}
+		return
+	})
+}
+
+// run reads and validates every row, and writes the valid rows BatchSize at a time, one transaction per batch.
+// A database error rolls back the current batch and stops the import, the summary then covers the committed batches.
+// A dry run writes all the batches in one transaction rolled back at the end, so a batch sees the keys of the previous ones.
+func (s *Import) run(r io.Reader, opt ImportOptions, newRow func() interface{}, write func([]importRow) (batchCounts, error)) (*dto.ImportSummary, error) {
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = ImportBatchSize
+	}
+	reader, e := importer.NewReader(opt.Format, r, newRow)
+	if e != nil {
+		return nil, e
+	}
+	summary := &dto.ImportSummary{DryRun: opt.DryRun, Errors: []dto.ImportRowError{}}
+	fail := func(number int, e error) {
+		summary.Failed++
+		if len(summary.Errors) < ImportMaxErrors {
+			summary.Errors = append(summary.Errors, dto.ImportRowError{Row: number, Error: e.Error()})
+		}
+	}
+	if opt.DryRun {
+		if e := s.ImportRepo.Begin(); e != nil {
+			return summary, e
+		}
+		defer s.ImportRepo.Rollback()
+	}
+	flush := func(batch []importRow) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !opt.DryRun {
+			if e := s.ImportRepo.Begin(); e != nil {
+				return e
+			}
+		}
+		counts, e := write(batch)
+		if e != nil {
+			if !opt.DryRun {
+				s.ImportRepo.Rollback()
+			}
+			return fmt.Errorf("batch of rows %d-%d: %v", batch[0].number, batch[len(batch)-1].number, e)
+		}
+		if !opt.DryRun {
+			if e := s.ImportRepo.Commit(); e != nil {
+				return e
+			}
+		}
+		summary.Inserted += counts.inserted
+		summary.Updated += counts.updated
+		summary.Skipped += counts.skipped
+		return nil
+	}
+
+	batch := []importRow{}
+	for {
+		row, number, e := reader.Next()
+		if e == io.EOF {
+			break
+		}
+		if _, ok := e.(*importer.RowError); !ok && e != nil {
+			return summary, e
+		}
+		summary.Rows++
+		if e == nil {
+			e = infra.Validate(row)
+		}
+		if e != nil {
+			fail(number, e)
+			continue
+		}
+		if batch = append(batch, importRow{number: number, row: row}); len(batch) >= opt.BatchSize {
+			if e := flush(batch); e != nil {
+				return summary, e
+			}
+			batch = batch[:0]
+		}
+	}
+	if e := flush(batch); e != nil {
+		return summary, e
+	}
+	s.Worker.Logger().Infof("import done, rows: %d, inserted: %d, updated: %d, skipped: %d, failed: %d, dry run: %t",
+		summary.Rows, summary.Inserted, summary.Updated, summary.Skipped, summary.Failed, opt.DryRun)
+	return summary, nil
+}
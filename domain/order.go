@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/8treenet/dump/domain/dto"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/freedom"
+)
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindService(func() *Order {
+			return &Order{}
+		})
+		initiator.InjectController(func(ctx freedom.Context) (service *Order) {
+			initiator.GetService(ctx, &service)
+			return
+		})
+	})
+}
+
+// Order reads orders and updates their status with optimistic concurrency, see Precondition.
+type Order struct {
+	Worker    freedom.Worker
+	OrderRepo OrderRepository
+}
+
+// Get .
+func (s *Order) Get(id int) (*po.Order, error) {
+	return s.OrderRepo.Order(id)
+}
+
+// Update applies update when check accepts the current version of the order,
+// infra.ErrPreconditionFailed is returned when the order changed meanwhile.
+func (s *Order) Update(id int, update dto.OrderUpdate, check Precondition) (*po.Order, error) {
+	order, e := s.OrderRepo.Order(id)
+	if e != nil {
+		return nil, e
+	}
+	version := order.Version
+	if e := check(infra.VersionETag(version), order.Updated); e != nil {
+		return nil, e
+	}
+
+	order.SetStatus(update.Status)
+	order.SetUpdated(time.Now())
+	saved, e := s.OrderRepo.SaveOrder(order, version)
+	if e != nil {
+		return nil, e
+	}
+	if !saved {
+		return nil, infra.ErrPreconditionFailed
+	}
+	return order, nil
+}
@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -8,10 +8,10 @@ import (
 // Admin .
 type Admin struct {
 	changes map[string]interface{}
-	ID      int       `gorm:"primary_key;column:id"`
-	Name    string    `gorm:"column:name"` // 管理员名称
-	Created time.Time `gorm:"column:created"`
-	Updated time.Time `gorm:"column:updated"`
+	ID      int       `gorm:"primary_key;column:id" json:"id"`
+	Name    string    `gorm:"column:name" json:"name" comment:"管理员名称"` // 管理员名称
+	Created time.Time `gorm:"column:created" json:"created"`
+	Updated time.Time `gorm:"column:updated" json:"updated"`
 }
 
 // TableName .
@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -8,9 +8,9 @@ import (
 // Albums .
 type Albums struct {
 	changes  map[string]interface{}
-	AlbumID  int    `gorm:"primary_key;column:AlbumId"`
-	Title    string `gorm:"column:Title"`
-	ArtistID int    `gorm:"column:ArtistId"`
+	AlbumID  int    `gorm:"primary_key;column:AlbumId" json:"albumID"`
+	Title    string `gorm:"column:Title" json:"title"`
+	ArtistID int    `gorm:"column:ArtistId" json:"artistID"`
 }
 
 // TableName .
@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,12 +9,12 @@ import (
 // Cart .
 type Cart struct {
 	changes map[string]interface{}
-	ID      int       `gorm:"primary_key;column:id"`
-	UserID  int       `gorm:"column:user_id"`  // 用户ID
-	GoodsID int       `gorm:"column:goods_id"` // 商品id
-	Num     int       `gorm:"column:num"`      // 数量
-	Created time.Time `gorm:"column:created"`
-	Updated time.Time `gorm:"column:updated"`
+	ID      int       `gorm:"primary_key;column:id" json:"id"`
+	UserID  int       `gorm:"column:user_id" json:"userID" comment:"用户ID"`   // 用户ID
+	GoodsID int       `gorm:"column:goods_id" json:"goodsID" comment:"商品id"` // 商品id
+	Num     int       `gorm:"column:num" json:"num" comment:"数量"`            // 数量
+	Created time.Time `gorm:"column:created" json:"created"`
+	Updated time.Time `gorm:"column:updated" json:"updated"`
 }
 
 // TableName .
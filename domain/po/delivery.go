@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,12 +9,12 @@ import (
 // Delivery .
 type Delivery struct {
 	changes        map[string]interface{}
-	ID             int       `gorm:"primary_key;column:id"`
-	AdminID        int       `gorm:"column:admin_id"` // 管理员id
-	OrderNo        string    `gorm:"column:order_no"`
-	TrackingNumber string    `gorm:"column:tracking_number"` // 快递单号
-	Created        time.Time `gorm:"column:created"`
-	Updated        time.Time `gorm:"column:updated"`
+	ID             int       `gorm:"primary_key;column:id" json:"id"`
+	AdminID        int       `gorm:"column:admin_id" json:"adminID" comment:"管理员id"` // 管理员id
+	OrderNo        string    `gorm:"column:order_no" json:"orderNo"`
+	TrackingNumber string    `gorm:"column:tracking_number" json:"trackingNumber" comment:"快递单号"` // 快递单号
+	Created        time.Time `gorm:"column:created" json:"created"`
+	Updated        time.Time `gorm:"column:updated" json:"updated"`
 }
 
 // TableName .
@@ -1,14 +1,12 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
-import ()
-
 // Dump .
 type Dump struct {
 	changes map[string]interface{}
-	ID      int    `gorm:"primary_key;column:id"`
-	Name    string `gorm:"column:name"` // 管理员名称
-	Dump    string `gorm:"column:dump"`
+	ID      int    `gorm:"primary_key;column:id" json:"id"`
+	Name    string `gorm:"column:name" json:"name" comment:"管理员名称"` // 管理员名称
+	Dump    string `gorm:"column:dump" json:"dump"`
 }
 
 // TableName .
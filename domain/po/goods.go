@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,13 +9,14 @@ import (
 // Goods .
 type Goods struct {
 	changes map[string]interface{}
-	ID      int       `gorm:"primary_key;column:id"`
-	Name    string    `gorm:"column:name"`  // 商品名称
-	Price   int       `gorm:"column:price"` // 价格
-	Stock   int       `gorm:"column:stock"` // 库存
-	Tag     string    `gorm:"column:tag"`   // 标签
-	Created time.Time `gorm:"column:created"`
-	Updated time.Time `gorm:"column:updated"`
+	ID      int       `gorm:"primary_key;column:id" json:"id"`
+	Name    string    `gorm:"column:name" json:"name" comment:"商品名称"` // 商品名称
+	Price   int       `gorm:"column:price" json:"price" comment:"价格"` // 价格
+	Stock   int       `gorm:"column:stock" json:"stock" comment:"库存"` // 库存
+	Tag     string    `gorm:"column:tag" json:"tag" comment:"标签"`     // 标签
+	Created time.Time `gorm:"column:created" json:"created"`
+	Updated time.Time `gorm:"column:updated" json:"updated"`
+	Version int       `gorm:"column:version" json:"version" comment:"版本"` // 版本
 }
 
 // TableName .
@@ -80,6 +81,12 @@ func (obj *Goods) SetUpdated(updated time.Time) {
 	obj.setChanges("updated", updated)
 }
 
+// SetVersion .
+func (obj *Goods) SetVersion(version int) {
+	obj.Version = version
+	obj.setChanges("version", version)
+}
+
 // AddPrice .
 func (obj *Goods) AddPrice(price int) {
 	obj.Price += price
@@ -91,3 +98,9 @@ func (obj *Goods) AddStock(stock int) {
 	obj.Stock += stock
 	obj.setChanges("stock", gorm.Expr("stock + ?", stock))
 }
+
+// AddVersion .
+func (obj *Goods) AddVersion(version int) {
+	obj.Version += version
+	obj.setChanges("version", gorm.Expr("version + ?", version))
+}
@@ -0,0 +1,20 @@
+package po
+
+// 敏感字段的脱敏策略, 作用于数据导出、接口输出和 orm 错误日志, masking.toml 可以按环境覆盖。
+// 此文件手写维护, 'server generate' 不会覆盖。
+
+// MaskingPolicy .
+func (obj *User) MaskingPolicy() map[string]string {
+	return map[string]string{
+		"password": "drop",
+		"name":     "fake",
+	}
+}
+
+// MaskingPolicy .
+func (obj *TestUsers) MaskingPolicy() map[string]string {
+	return map[string]string{
+		"password":  "drop",
+		"user_name": "fake",
+	}
+}
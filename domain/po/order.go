@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,13 +9,14 @@ import (
 // Order .
 type Order struct {
 	changes    map[string]interface{}
-	ID         int       `gorm:"primary_key;column:id"`
-	OrderNo    string    `gorm:"column:order_no"`
-	UserID     int       `gorm:"column:user_id"`     // 用户id
-	TotalPrice int       `gorm:"column:total_price"` // 总价
-	Status     string    `gorm:"column:status"`      // 支付,未支付，发货，完成
-	Created    time.Time `gorm:"column:created"`
-	Updated    time.Time `gorm:"column:updated"`
+	ID         int       `gorm:"primary_key;column:id" json:"id"`
+	OrderNo    string    `gorm:"column:order_no" json:"orderNo"`
+	UserID     int       `gorm:"column:user_id" json:"userID" comment:"用户id"`        // 用户id
+	TotalPrice int       `gorm:"column:total_price" json:"totalPrice" comment:"总价"`  // 总价
+	Status     string    `gorm:"column:status" json:"status" comment:"支付,未支付，发货，完成"` // 支付,未支付，发货，完成
+	Created    time.Time `gorm:"column:created" json:"created"`
+	Updated    time.Time `gorm:"column:updated" json:"updated"`
+	Version    int       `gorm:"column:version" json:"version" comment:"版本"` // 版本
 }
 
 // TableName .
@@ -80,6 +81,12 @@ func (obj *Order) SetUpdated(updated time.Time) {
 	obj.setChanges("updated", updated)
 }
 
+// SetVersion .
+func (obj *Order) SetVersion(version int) {
+	obj.Version = version
+	obj.setChanges("version", version)
+}
+
 // AddUserID .
 func (obj *Order) AddUserID(userID int) {
 	obj.UserID += userID
@@ -91,3 +98,9 @@ func (obj *Order) AddTotalPrice(totalPrice int) {
 	obj.TotalPrice += totalPrice
 	obj.setChanges("total_price", gorm.Expr("total_price + ?", totalPrice))
 }
+
+// AddVersion .
+func (obj *Order) AddVersion(version int) {
+	obj.Version += version
+	obj.setChanges("version", gorm.Expr("version + ?", version))
+}
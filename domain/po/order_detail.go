@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,13 +9,13 @@ import (
 // OrderDetail .
 type OrderDetail struct {
 	changes   map[string]interface{}
-	ID        int       `gorm:"primary_key;column:id"`
-	OrderNo   string    `gorm:"column:order_no"`   // 订单id
-	GoodsID   int       `gorm:"column:goods_id"`   // 商品id
-	Num       int       `gorm:"column:num"`        // 数量
-	GoodsName string    `gorm:"column:goods_name"` // 商品名称
-	Created   time.Time `gorm:"column:created"`
-	Updated   time.Time `gorm:"column:updated"`
+	ID        int       `gorm:"primary_key;column:id" json:"id"`
+	OrderNo   string    `gorm:"column:order_no" json:"orderNo" comment:"订单id"`     // 订单id
+	GoodsID   int       `gorm:"column:goods_id" json:"goodsID" comment:"商品id"`     // 商品id
+	Num       int       `gorm:"column:num" json:"num" comment:"数量"`                // 数量
+	GoodsName string    `gorm:"column:goods_name" json:"goodsName" comment:"商品名称"` // 商品名称
+	Created   time.Time `gorm:"column:created" json:"created"`
+	Updated   time.Time `gorm:"column:updated" json:"updated"`
 }
 
 // TableName .
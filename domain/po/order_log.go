@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -8,9 +8,9 @@ import (
 // OrderLog .
 type OrderLog struct {
 	changes map[string]interface{}
-	ID      int    `gorm:"primary_key;column:id"`
-	OrderID int    `gorm:"column:order_id"`
-	Desc    string `gorm:"column:desc"`
+	ID      int    `gorm:"primary_key;column:id" json:"id"`
+	OrderID int    `gorm:"column:order_id" json:"orderID"`
+	Desc    string `gorm:"column:desc" json:"desc"`
 }
 
 // TableName .
@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -8,10 +8,10 @@ import (
 // Product .
 type Product struct {
 	changes map[string]interface{}
-	ID      int    `gorm:"primary_key;column:id"`
-	Price   int    `gorm:"column:price"`
-	Desc    string `gorm:"column:desc"`
-	Test    int    `gorm:"column:test"`
+	ID      int    `gorm:"primary_key;column:id" json:"id"`
+	Price   int    `gorm:"column:price" json:"price"`
+	Desc    string `gorm:"column:desc" json:"desc"`
+	Test    int    `gorm:"column:test" json:"test"`
 }
 
 // TableName .
@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,13 +9,13 @@ import (
 // TestEmails .
 type TestEmails struct {
 	changes    map[string]interface{}
-	ID         int       `gorm:"primary_key;column:id"`
-	CreatedAt  time.Time `gorm:"column:created_at"`
-	UpdatedAt  time.Time `gorm:"column:updated_at"`
-	DeletedAt  time.Time `gorm:"column:deleted_at"`
-	TypeID     int       `gorm:"column:type_id"`
-	Subscribed int       `gorm:"column:subscribed"`
-	TestUserID int       `gorm:"column:test_user_id"`
+	ID         int        `gorm:"primary_key;column:id" json:"id"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+	DeletedAt  *time.Time `gorm:"column:deleted_at" json:"deletedAt"`
+	TypeID     int        `gorm:"column:type_id" json:"typeID"`
+	Subscribed int        `gorm:"column:subscribed" json:"subscribed"`
+	TestUserID int        `gorm:"column:test_user_id" json:"testUserID"`
 }
 
 // TableName .
@@ -58,10 +58,16 @@ func (obj *TestEmails) SetUpdatedAt(updatedAt time.Time) {
 
 // SetDeletedAt .
 func (obj *TestEmails) SetDeletedAt(deletedAt time.Time) {
-	obj.DeletedAt = deletedAt
+	obj.DeletedAt = &deletedAt
 	obj.setChanges("deleted_at", deletedAt)
 }
 
+// SetDeletedAtNull .
+func (obj *TestEmails) SetDeletedAtNull() {
+	obj.DeletedAt = nil
+	obj.setChanges("deleted_at", nil)
+}
+
 // SetTypeID .
 func (obj *TestEmails) SetTypeID(typeID int) {
 	obj.TypeID = typeID
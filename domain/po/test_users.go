@@ -1,23 +1,22 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
-	"time"
-
 	"github.com/jinzhu/gorm"
+	"time"
 )
 
 // TestUsers .
 type TestUsers struct {
 	changes   map[string]interface{}
-	ID        int       `gorm:"primary_key;column:id"`
-	CreatedAt time.Time `gorm:"column:created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at"`
-	DeletedAt time.Time `gorm:"column:deleted_at"`
-	UserName  string    `gorm:"column:user_name"`
-	Password  string    `gorm:"column:password"`
-	Age       int       `gorm:"column:age"`
-	Status    int       `gorm:"column:status"`
+	ID        int        `gorm:"primary_key;column:id" json:"id"`
+	CreatedAt time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+	DeletedAt *time.Time `gorm:"column:deleted_at" json:"deletedAt"`
+	UserName  string     `gorm:"column:user_name" json:"userName"`
+	Password  string     `gorm:"column:password" json:"password"`
+	Age       int        `gorm:"column:age" json:"age"`
+	Status    int        `gorm:"column:status" json:"status"`
 }
 
 // TableName .
@@ -60,10 +59,16 @@ func (obj *TestUsers) SetUpdatedAt(updatedAt time.Time) {
 
 // SetDeletedAt .
 func (obj *TestUsers) SetDeletedAt(deletedAt time.Time) {
-	obj.DeletedAt = deletedAt
+	obj.DeletedAt = &deletedAt
 	obj.setChanges("deleted_at", deletedAt)
 }
 
+// SetDeletedAtNull .
+func (obj *TestUsers) SetDeletedAtNull() {
+	obj.DeletedAt = nil
+	obj.setChanges("deleted_at", nil)
+}
+
 // SetUserName .
 func (obj *TestUsers) SetUserName(userName string) {
 	obj.UserName = userName
@@ -1,4 +1,4 @@
-//Package po generated by 'freedom new-po'
+// Package po generated by 'server generate'
 package po
 
 import (
@@ -9,12 +9,12 @@ import (
 // User .
 type User struct {
 	changes  map[string]interface{}
-	ID       int       `gorm:"primary_key;column:id"` // 用户id
-	Name     string    `gorm:"column:name"`           // 用户名称
-	Money    int       `gorm:"column:money"`          // 金钱
-	Password string    `gorm:"column:password"`       // 密码
-	Created  time.Time `gorm:"column:created"`
-	Updated  time.Time `gorm:"column:updated"`
+	ID       int       `gorm:"primary_key;column:id" json:"id" comment:"用户id"` // 用户id
+	Name     string    `gorm:"column:name" json:"name" comment:"用户名称"`         // 用户名称
+	Money    int       `gorm:"column:money" json:"money" comment:"金钱"`         // 金钱
+	Password string    `gorm:"column:password" json:"password" comment:"密码"`   // 密码
+	Created  time.Time `gorm:"column:created" json:"created"`
+	Updated  time.Time `gorm:"column:updated" json:"updated"`
 }
 
 // TableName .
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/export"
+)
+
+// The services use the repositories through these interfaces, the framework injects the bound gorm repository
+// implementing them, tests use the in-memory fakes of the repositorytest package, e.g. repositorytest.NewMemoryImport.
+
+// DefaultRepository is implemented by repository.Default and repositorytest.MemoryDefault.
+type DefaultRepository interface {
+	GetIP() string
+	GetUA() string
+}
+
+// ExportRepository is implemented by repository.Export and repositorytest.MemoryExport.
+// query is a list of "column op ?" conditions joined by AND, op is =, <>, <, <=, >, >=, LIKE or IN.
+type ExportRepository interface {
+	Orders(batchSize int, query string, args ...interface{}) export.Rows
+	OrderDetails(batchSize int, query string, args ...interface{}) export.Rows
+	Deliveries(batchSize int, query string, args ...interface{}) export.Rows
+	Goods(batchSize int, query string, args ...interface{}) export.Rows
+	// Detached returns a repository whose rows can be read after the request, without its worker.
+	Detached() ExportRepository
+}
+
+// ImportRepository is implemented by repository.Import and repositorytest.MemoryImport.
+type ImportRepository interface {
+	Begin() error
+	Commit() error
+	Rollback() error
+	GoodsByNames(names []string) (map[string]*po.Goods, error)
+	CreateGoods(goods *po.Goods) error
+	SaveGoods(goods *po.Goods) error
+	UsersByNames(names []string) (map[string]*po.User, error)
+	CreateUser(user *po.User) error
+	SaveUser(user *po.User) error
+}
+
+// GoodsRepository is implemented by repository.Goods and repositorytest.MemoryGoods.
+type GoodsRepository interface {
+	Goods(id int) (*po.Goods, error)
+	AllGoods() (infra.RowIterator, error)
+	// SaveGoods saves the changes of goods if its row is still at version, it reports whether it did.
+	// A save increments the version of the row and of goods.
+	SaveGoods(goods *po.Goods, version int) (bool, error)
+}
+
+// OrderRepository is implemented by repository.Order and repositorytest.MemoryOrder.
+type OrderRepository interface {
+	Order(id int) (*po.Order, error)
+	// SaveOrder saves the changes of order if its row is still at version, it reports whether it did.
+	// A save increments the version of the row and of order.
+	SaveOrder(order *po.Order, version int) (bool, error)
+}
@@ -4,8 +4,17 @@ go 1.13
 
 require (
 	github.com/8treenet/freedom v1.8.2
+	github.com/andybalholm/brotli v1.0.0
 	github.com/go-redis/redis v6.15.6+incompatible
 	github.com/jinzhu/gorm v1.9.12
 	github.com/kataras/iris/v12 v12.1.8
+	github.com/prometheus/client_golang v1.6.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	golang.org/x/net v0.0.0-20200520004742-59133d7f0dd7
 	gopkg.in/go-playground/validator.v9 v9.31.0
+	gopkg.in/yaml.v3 v3.0.0-20191120175047-4206685974f2
 )
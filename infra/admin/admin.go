@@ -0,0 +1,262 @@
+// Package admin serves the diagnostics of the process on its own listener, away from the public routes:
+// pprof, goroutine dumps, GC stats, the effective configuration and the registered pool stats.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	iriscontext "github.com/kataras/iris/v12/context"
+)
+
+// TokenHeader carries the token, ?token= is accepted too for go tool pprof.
+const TokenHeader = "X-Admin-Token"
+
+// Stats returns a JSON encodable snapshot, e.g. sql.DB.Stats().
+type Stats func() interface{}
+
+var (
+	stats   = map[string]Stats{}
+	statsMu sync.RWMutex
+	// Config writes the effective configuration, secrets redacted. It is set by main.
+	Config func(w io.Writer)
+)
+
+// Register adds the stats served by /debug/stats, e.g. the database and Redis pools.
+func Register(name string, stat Stats) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats[name] = stat
+}
+
+// Handler serves the admin endpoints, every request must carry token when it is not empty.
+func Handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", index)
+	mux.HandleFunc("/debug/pprof/", profiles)
+	mux.HandleFunc("/debug/pprof/profile", cpuProfile)
+	mux.HandleFunc("/debug/pprof/trace", executionTrace)
+	mux.HandleFunc("/debug/goroutines", goroutines)
+	mux.HandleFunc("/debug/gc", gc)
+	mux.HandleFunc("/debug/config", config)
+	mux.HandleFunc("/debug/stats", poolStats)
+	if token == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(r, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Authorized reports whether r carries token, always false when token is empty.
+func Authorized(r *http.Request, token string) bool {
+	given := r.Header.Get(TokenHeader)
+	if given == "" {
+		given = r.URL.Query().Get("token")
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// NewGuard requires the token on the routes of the application under prefix, e.g. /admin/ of the exports.
+// token is read on every request, so a reloaded token applies at once; the routes are denied while it is empty.
+func NewGuard(prefix string, token func() string) iriscontext.Handler {
+	return func(ctx iriscontext.Context) {
+		if strings.HasPrefix(ctx.Path(), prefix) && !Authorized(ctx.Request(), token()) {
+			ctx.StatusCode(http.StatusUnauthorized)
+			ctx.StopExecution()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// Serve listens on addr and serves Handler in the background, the listen error is returned at once.
+func Serve(addr, token string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	//不设置写超时, profile和trace默认采样30秒
+	server := &http.Server{Handler: Handler(token), ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	return server, nil
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, path := range []string{"/debug/pprof/", "/debug/goroutines", "/debug/gc", "/debug/config", "/debug/stats"} {
+		fmt.Fprintln(w, path)
+	}
+}
+
+// goroutines dumps the stacks of all goroutines, as a panic prints them.
+func goroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// profiles serves the runtime profiles, e.g. go tool pprof http://127.0.0.1:6060/debug/pprof/heap.
+// net/http/pprof is not imported: it registers its handlers on http.DefaultServeMux, which may be public.
+func profiles(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/debug/pprof/")
+	if name == "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, profile := range pprof.Profiles() {
+			fmt.Fprintf(w, "/debug/pprof/%s %d\n", profile.Name(), profile.Count())
+		}
+		fmt.Fprintln(w, "/debug/pprof/profile?seconds=30")
+		fmt.Fprintln(w, "/debug/pprof/trace?seconds=5")
+		return
+	}
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.Query().Get("gc") == "1" && name == "heap" {
+		runtime.GC()
+	}
+	debugLevel, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+	if debugLevel > 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	profile.WriteTo(w, debugLevel)
+}
+
+// cpuProfile records the CPU profile for ?seconds=, 30 by default.
+func cpuProfile(w http.ResponseWriter, r *http.Request) {
+	duration := seconds(r, 30)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := pprof.StartCPUProfile(w); err != nil {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		http.Error(w, err.Error(), http.StatusConflict) //同时只能有一个CPU profile
+		return
+	}
+	sleep(r, duration)
+	pprof.StopCPUProfile()
+}
+
+// executionTrace records the execution trace for ?seconds=, 1 by default, read with go tool trace.
+func executionTrace(w http.ResponseWriter, r *http.Request) {
+	duration := seconds(r, 1)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if err := trace.Start(w); err != nil {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	sleep(r, duration)
+	trace.Stop()
+}
+
+func seconds(r *http.Request, fallback int) time.Duration {
+	value, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+	if err != nil || value <= 0 {
+		value = fallback
+	}
+	return time.Duration(value) * time.Second
+}
+
+// sleep waits for duration or until the client goes away.
+func sleep(r *http.Request, duration time.Duration) {
+	select {
+	case <-time.After(duration):
+	case <-r.Context().Done():
+	}
+}
+
+// gc serves the memory and garbage collector stats, ?run=1 runs a collection first.
+func gc(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("run") == "1" {
+		runtime.GC()
+	}
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	var collector debug.GCStats
+	collector.PauseQuantiles = make([]time.Duration, 5)
+	debug.ReadGCStats(&collector)
+	writeJSON(w, map[string]interface{}{
+		"goroutines": runtime.NumGoroutine(),
+		"memory": map[string]interface{}{
+			"alloc":           memory.Alloc,
+			"total_alloc":     memory.TotalAlloc,
+			"sys":             memory.Sys,
+			"heap_alloc":      memory.HeapAlloc,
+			"heap_inuse":      memory.HeapInuse,
+			"heap_idle":       memory.HeapIdle,
+			"heap_released":   memory.HeapReleased,
+			"heap_objects":    memory.HeapObjects,
+			"stack_inuse":     memory.StackInuse,
+			"next_gc":         memory.NextGC,
+			"mallocs":         memory.Mallocs,
+			"frees":           memory.Frees,
+			"gc_cpu_fraction": memory.GCCPUFraction,
+		},
+		"gc": map[string]interface{}{
+			"num_gc":          collector.NumGC,
+			"last_gc":         collector.LastGC,
+			"pause_total":     collector.PauseTotal.String(),
+			"pause_quantiles": durations(collector.PauseQuantiles), //最小值, 25%, 50%, 75%, 最大值
+		},
+	})
+}
+
+func config(w http.ResponseWriter, r *http.Request) {
+	if Config == nil {
+		http.Error(w, "configuration not available", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	Config(w)
+}
+
+func poolStats(w http.ResponseWriter, r *http.Request) {
+	statsMu.RLock()
+	result := map[string]interface{}{}
+	for name, stat := range stats {
+		result[name] = stat()
+	}
+	statsMu.RUnlock()
+	writeJSON(w, result)
+}
+
+func durations(list []time.Duration) []string {
+	result := []string{}
+	for _, item := range list {
+		result = append(result, item.String())
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(content)
+	w.Write([]byte("\n"))
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/8treenet/dump/server/conf"
+	"github.com/8treenet/freedom"
+)
+
+// TestAuthorized checks the header and query tokens, and that an empty token denies every request.
+func TestAuthorized(t *testing.T) {
+	cases := []struct {
+		target, header, token string
+		expected              bool
+	}{
+		{"/admin/exports/orders", "secret", "secret", true},
+		{"/admin/exports/orders?token=secret", "", "secret", true},
+		{"/admin/exports/orders?token=wrong", "", "secret", false},
+		{"/admin/exports/orders", "", "secret", false},
+		{"/admin/exports/orders", "", "", false},
+		{"/admin/exports/orders?token=", "", "", false},
+	}
+	for _, item := range cases {
+		r := httptest.NewRequest("GET", item.target, nil)
+		if item.header != "" {
+			r.Header.Set(TokenHeader, item.header)
+		}
+		if result := Authorized(r, item.token); result != item.expected {
+			t.Errorf("%s with header %q and token %q: %v, expected %v", item.target, item.header, item.token, result, item.expected)
+		}
+	}
+}
+
+// TestHandler checks the stats, the redacted configuration and a heap profile, and that a token is required once set.
+func TestHandler(t *testing.T) {
+	Register("pool", func() interface{} { return map[string]int{"open": 3} })
+	defer func() {
+		statsMu.Lock()
+		delete(stats, "pool")
+		statsMu.Unlock()
+	}()
+	//与main中的installAdmin相同, 输出conf的Print
+	app := freedom.DefaultConfiguration()
+	app.Other = map[string]interface{}{"admin_token": "token-secret"}
+	cfg := &conf.Configuration{
+		App:     &app,
+		DB:      &conf.DBConf{Driver: "mysql", Addr: "root:db-secret@tcp(127.0.0.1:3306)/dump"},
+		Redis:   &conf.RedisConf{Addr: "127.0.0.1:6379", Password: "redis-secret"},
+		Masking: &conf.MaskingConf{Salt: "salt-secret"},
+	}
+	Config = cfg.Print
+	defer func() { Config = nil }()
+
+	server := httptest.NewServer(Handler("token-secret"))
+	defer server.Close()
+	get := func(path, token string) (*http.Response, []byte) {
+		t.Helper()
+		r, err := http.NewRequest("GET", server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			r.Header.Set(TokenHeader, token)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		if _, err := io.Copy(&body, resp.Body); err != nil {
+			t.Fatal(err)
+		}
+		return resp, body.Bytes()
+	}
+
+	for _, path := range []string{"/debug/stats", "/debug/config", "/debug/pprof/heap"} {
+		if resp, _ := get(path, ""); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s without the token: status %d, expected 401", path, resp.StatusCode)
+		}
+	}
+
+	resp, body := get("/debug/stats", "token-secret")
+	result := map[string]map[string]int{}
+	if err := json.Unmarshal(body, &result); err != nil || resp.StatusCode != http.StatusOK || result["pool"]["open"] != 3 {
+		t.Errorf("/debug/stats: status %d, %s", resp.StatusCode, body)
+	}
+
+	resp, body = get("/debug/config", "token-secret")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "[db]") {
+		t.Errorf("/debug/config: status %d, %s", resp.StatusCode, body)
+	}
+	for _, secret := range []string{"db-secret", "redis-secret", "salt-secret", "token-secret"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("/debug/config shows %s:\n%s", secret, body)
+		}
+	}
+
+	resp, body = get("/debug/pprof/heap", "token-secret")
+	if resp.StatusCode != http.StatusOK || len(body) == 0 {
+		t.Errorf("/debug/pprof/heap: status %d, %d bytes", resp.StatusCode, len(body))
+	}
+	resp, body = get("/debug/pprof/heap?debug=1", "token-secret")
+	if !strings.Contains(string(body), "heap profile") {
+		t.Errorf("/debug/pprof/heap?debug=1: status %d, %.100s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,150 @@
+// Package apptest runs the freedom application on an in-memory SQLite database for tests,
+// without MySQL or Redis.
+//
+//	func TestMain(m *testing.M) {
+//		harness, err := apptest.New(apptest.Options{FixturesDir: "../server/fixtures"})
+//		if err != nil {
+//			panic(err)
+//		}
+//		code := m.Run()
+//		harness.Close()
+//		os.Exit(code)
+//	}
+//
+// The controllers, services and repositories are those imported by the test package,
+// the Go migrations those of the imported migration packages, e.g.
+// import _ "github.com/8treenet/dump/server/migrations".
+// The freedom application is a process singleton, create one Harness per test binary.
+package apptest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/8treenet/dump/adapter/repository"
+	"github.com/8treenet/dump/infra/fixture"
+	"github.com/8treenet/dump/infra/metrics"
+	"github.com/8treenet/dump/infra/migration"
+	"github.com/8treenet/dump/infra/tracing"
+	"github.com/8treenet/freedom"
+	"github.com/8treenet/freedom/middleware"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/kataras/iris/v12"
+)
+
+// Options .
+type Options struct {
+	// MigrationsDir contains the SQL migrations, applied with the registered Go migrations.
+	MigrationsDir string
+	// FixturesDir is loaded once the migrations are applied, see fixture.Loader.LoadDir.
+	FixturesDir string
+	// Install installs the middleware of the application,
+	// by default the recover, tracing, metrics, trace and request logger middleware of the server.
+	// Spans are recorded once an exporter is installed, e.g. tracing.InstallSync(tracetest.NewInMemoryExporter(), "test").
+	Install func(app freedom.Application)
+}
+
+// Harness is the application served on a local port.
+type Harness struct {
+	DB     *gorm.DB
+	Client *Client
+	app    freedom.Application
+}
+
+var databases int64
+
+// NewDB opens a new in-memory SQLite database and applies the migrations.
+func NewDB(migrationsDir string) (*gorm.DB, error) {
+	name := fmt.Sprintf("file:apptest%d?mode=memory&cache=shared", atomic.AddInt64(&databases, 1))
+	db, err := gorm.Open("sqlite3", name)
+	if err != nil {
+		return nil, err
+	}
+	//内存数据库在最后一个连接关闭时销毁
+	db.DB().SetMaxIdleConns(4)
+	db.DB().SetConnMaxLifetime(0)
+	db.LogMode(false)
+	repository.InstallSlowLog(db)
+	repository.InstallTracing(db)
+
+	migrator := migration.New(db, migrationsDir)
+	migrator.Out = ioutil.Discard
+	if err := migrator.Up(0); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// New creates the database, loads the fixtures and runs the application.
+func New(opt Options) (*Harness, error) {
+	db, err := NewDB(opt.MigrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	installCalls()
+	result := &Harness{DB: db, app: freedom.NewApplication()}
+	if opt.FixturesDir != "" {
+		if err := result.LoadFixtures(opt.FixturesDir); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	result.app.InstallDB(func() interface{} {
+		return db
+	})
+	if opt.Install == nil {
+		opt.Install = installMiddleware
+	}
+	opt.Install(result.app)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	//监听在Run之前建立，请求会等待到服务启动
+	go result.app.Run(iris.Listener(listener), freedom.DefaultConfiguration())
+	result.Client = NewClient("http://" + listener.Addr().String())
+	return result, nil
+}
+
+func installMiddleware(app freedom.Application) {
+	app.InstallMiddleware(middleware.NewRecover())
+	app.InstallMiddleware(tracing.NewMiddleware())
+	app.InstallMiddleware(metrics.NewMiddleware())
+	app.InstallMiddleware(middleware.NewTrace("x-request-id"))
+	app.InstallMiddleware(middleware.NewRequestLogger("x-request-id"))
+}
+
+// LoadFixtures replaces the rows of the tables of dir.
+func (h *Harness) LoadFixtures(dir string) error {
+	loader := fixture.New(h.DB)
+	loader.Truncate = true
+	_, err := loader.LoadDir(dir)
+	return err
+}
+
+// Load replaces the rows of table, e.g. h.Load("goods", []map[string]interface{}{{"id": 1, "name": "apple"}}).
+func (h *Harness) Load(table string, rows []map[string]interface{}) error {
+	loader := fixture.New(h.DB)
+	loader.Truncate = true
+	return loader.Load(table, rows)
+}
+
+// Close stops the application and drops the database.
+func (h *Harness) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.app.Iris().Shutdown(ctx); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return h.DB.Close()
+}
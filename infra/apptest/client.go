@@ -0,0 +1,121 @@
+package apptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Client sends requests to the application through its middleware chain.
+type Client struct {
+	BaseURL string
+	// Header is added to every request.
+	Header http.Header
+	HTTP   *http.Client
+}
+
+// NewClient .
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL, Header: http.Header{}, HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Response .
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Envelope is the body of an infra.JSONResponse.
+type Envelope struct {
+	Code  int             `json:"code"`
+	Error string          `json:"error"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// JSON decodes the body into obj.
+func (r *Response) JSON(obj interface{}) error {
+	return json.Unmarshal(r.Body, obj)
+}
+
+// Envelope decodes the body of an infra.JSONResponse, data into obj when it is not nil.
+func (r *Response) Envelope(data interface{}) (*Envelope, error) {
+	result := &Envelope{}
+	if err := json.Unmarshal(r.Body, result); err != nil {
+		return nil, fmt.Errorf("status %d: %v: %s", r.StatusCode, err, r.Body)
+	}
+	if data != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Do sends a request, body is a []byte, a string, an io.Reader or a value sent as JSON.
+func (c *Client) Do(method, path string, body interface{}, header http.Header) (*Response, error) {
+	var reader io.Reader
+	contentType := ""
+	switch value := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(value)
+	case string:
+		reader = bytes.NewReader([]byte(value))
+	case io.Reader:
+		reader = value
+	default:
+		content, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		reader, contentType = bytes.NewReader(content), "application/json"
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, headers := range []http.Header{c.Header, header} {
+		for key, values := range headers {
+			req.Header[key] = values
+		}
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: content}, nil
+}
+
+// Get .
+func (c *Client) Get(path string) (*Response, error) {
+	return c.Do(http.MethodGet, path, nil, nil)
+}
+
+// Post .
+func (c *Client) Post(path string, body interface{}) (*Response, error) {
+	return c.Do(http.MethodPost, path, body, nil)
+}
+
+// Put .
+func (c *Client) Put(path string, body interface{}) (*Response, error) {
+	return c.Do(http.MethodPut, path, body, nil)
+}
+
+// Delete .
+func (c *Client) Delete(path string) (*Response, error) {
+	return c.Do(http.MethodDelete, path, nil, nil)
+}
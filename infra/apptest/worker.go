@@ -0,0 +1,93 @@
+package apptest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/8treenet/freedom"
+)
+
+// installCalls registers the route of Call, New installs it so that only a Harness serves it.
+func installCalls() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		current = initiator
+		initiator.BindController(callPath, &CallController{})
+	})
+}
+
+const callPath = "/__apptest/calls"
+
+var (
+	current freedom.Initiator
+	calls   = map[string]func(worker freedom.Worker){}
+	callsMu sync.Mutex
+	callID  int64
+)
+
+// CallController runs the functions of Harness.Call with the worker of the request.
+type CallController struct {
+	Worker freedom.Worker
+}
+
+// PostBy handles the POST: /__apptest/calls/{id:string} route.
+func (c *CallController) PostBy(id string) (result string) {
+	callsMu.Lock()
+	fn, ok := calls[id]
+	callsMu.Unlock()
+	if !ok {
+		c.Worker.IrisContext().StatusCode(http.StatusNotFound)
+		return "call not found"
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			c.Worker.IrisContext().StatusCode(http.StatusInternalServerError)
+			result = fmt.Sprint(r)
+		}
+	}()
+	fn(c.Worker)
+	return "ok"
+}
+
+// Call runs fn inside a request of the application, worker is the worker of that request,
+// with its logger, transaction and bus, as repositories and services get it from the framework.
+// A repository is used by setting its worker, e.g. repo := &repository.Goods{}; repo.Worker = worker.
+// A panic of fn is returned as an error.
+//
+// There is no fake freedom.Worker: the services are resolved from the iris context of the worker,
+// FetchDB reads the transaction of the request from its Store, and its logger and bus are built by
+// the framework middleware from the request headers. A fake would skip what Call is meant to exercise,
+// the tests of the services without the framework use the in-memory repositories instead.
+func (h *Harness) Call(fn func(worker freedom.Worker)) error {
+	id := strconv.FormatInt(atomic.AddInt64(&callID, 1), 10)
+	callsMu.Lock()
+	calls[id] = fn
+	callsMu.Unlock()
+	defer func() {
+		callsMu.Lock()
+		delete(calls, id)
+		callsMu.Unlock()
+	}()
+
+	resp, err := h.Client.Post(callPath+"/"+id, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("call %s: status %d: %s", id, resp.StatusCode, resp.Body)
+	}
+	return nil
+}
+
+// Service sets service, a pointer to a service pointer, to the service of worker, e.g.
+//
+//	harness.Call(func(worker freedom.Worker) {
+//		var service *domain.Import
+//		apptest.Service(worker, &service)
+//		summary, err := service.Goods(strings.NewReader(content), domain.ImportOptions{Format: "csv"})
+//	})
+func Service(worker freedom.Worker, service interface{}) {
+	current.GetService(worker.IrisContext(), service)
+}
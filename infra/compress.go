@@ -0,0 +1,148 @@
+package infra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/kataras/iris/v12/context"
+)
+
+// CompressThreshold is the minimum size in bytes of a response body to be compressed.
+var CompressThreshold = 1024
+
+// ResponseLogLimit bounds how many bytes of the response body are retained in ctx.Values()["response"] for the request logger.
+var ResponseLogLimit = 4096
+
+// Encoder creates a compressing writer for a content coding.
+type Encoder func(w io.Writer) (io.WriteCloser, error)
+
+type encoding struct {
+	name    string
+	encoder Encoder
+}
+
+// encodings in order of server preference.
+var encodings = []encoding{
+	{"br", func(w io.Writer) (io.WriteCloser, error) {
+		return brotli.NewWriterLevel(w, brotli.DefaultCompression), nil
+	}},
+	{"gzip", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, gzip.DefaultCompression)
+	}},
+	//deflate是zlib格式(RFC 1950), 不是裸的DEFLATE流
+	{"deflate", func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, zlib.DefaultCompression)
+	}},
+}
+
+// RegisterEncoder registers a content coding, e.g. "zstd".
+// Registered encoders are preferred over the built-in br, gzip and deflate.
+func RegisterEncoder(name string, encoder Encoder) {
+	encodings = append([]encoding{{name, encoder}}, encodings...)
+}
+
+// negotiateEncoding picks a content coding from the Accept-Encoding header.
+func negotiateEncoding(header string) (string, Encoder) {
+	if header == "" {
+		return "", nil
+	}
+	qualities := map[string]float64{}
+	for _, item := range strings.Split(header, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		quality := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		qualities[name] = quality
+	}
+
+	candidates := []encoding{}
+	for _, enc := range encodings {
+		quality, ok := qualities[enc.name]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > 0 {
+			candidates = append(candidates, enc)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return qualities[candidates[i].name] > qualities[candidates[j].name]
+	})
+	return candidates[0].name, candidates[0].encoder
+}
+
+// acceptedEncoding picks the content coding of a body of size bytes, "" below CompressThreshold.
+func acceptedEncoding(ctx context.Context, size int) (string, Encoder) {
+	if size < CompressThreshold {
+		return "", nil
+	}
+	return negotiateEncoding(ctx.GetHeader("Accept-Encoding"))
+}
+
+// compressContent compresses content with encoder, it returns the content unchanged and "" when compressing fails.
+func compressContent(content []byte, name string, encoder Encoder) ([]byte, string) {
+	if encoder == nil {
+		return content, ""
+	}
+	var buf bytes.Buffer
+	writer, err := encoder(&buf)
+	if err != nil {
+		return content, ""
+	}
+	if _, err = writer.Write(content); err != nil {
+		return content, ""
+	}
+	if err = writer.Close(); err != nil {
+		return content, ""
+	}
+	return buf.Bytes(), name
+}
+
+// EncodedETag returns the ETag of the representation of etag's resource in a content coding,
+// e.g. "abc" and gzip give "abc-gzip". The compressed bytes differ from the identity ones,
+// so a strong ETag can't be shared between them.
+func EncodedETag(etag, encoding string) string {
+	if etag == "" || encoding == "" || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + encoding + `"`
+}
+
+// identityETag strips the content coding suffix added by EncodedETag.
+func identityETag(etag string) string {
+	index := strings.LastIndex(etag, "-")
+	if index < 0 || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	suffix := etag[index+1 : len(etag)-1]
+	for _, enc := range encodings {
+		if enc.name == suffix {
+			return etag[:index] + `"`
+		}
+	}
+	return etag
+}
+
+// logContent returns the response body retained for the request logger.
+func logContent(content []byte) string {
+	if ResponseLogLimit >= 0 && len(content) > ResponseLogLimit {
+		return string(content[:ResponseLogLimit]) + "...(truncated)"
+	}
+	return string(content)
+}
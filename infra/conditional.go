@@ -0,0 +1,105 @@
+package infra
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ErrPreconditionFailed is returned by Request.CheckPrecondition when the client's copy is stale.
+// JSONResponse writes it with the 412 status code.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ContentETag returns a strong ETag of the serialized payload.
+func ContentETag(content []byte) string {
+	sum := sha1.Sum(content)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// VersionETag returns a strong ETag of a po's Version, which every save increments.
+// Unlike the Updated column, limited to seconds on MySQL, it changes with every write.
+func VersionETag(version int) string {
+	return `"v` + strconv.Itoa(version) + `"`
+}
+
+// CheckPrecondition validates If-Match and If-Unmodified-Since of PUT/PATCH/DELETE requests
+// against the current version of the resource, e.g. VersionETag(goods.Version) and goods.Updated.
+func (req *Request) CheckPrecondition(etag string, lastModified time.Time) error {
+	ctx := req.Worker.IrisContext()
+	switch ctx.Method() {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil
+	}
+
+	if ifMatch := ctx.GetHeader("If-Match"); ifMatch != "" {
+		if !matchETag(ifMatch, etag, false) {
+			return ErrPreconditionFailed
+		}
+		return nil
+	}
+
+	if lastModified.IsZero() {
+		return nil
+	}
+	since, err := http.ParseTime(ctx.GetHeader("If-Unmodified-Since"))
+	if err != nil {
+		return nil
+	}
+	if lastModified.Truncate(time.Second).After(since) {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+// notModified reports whether a GET/HEAD request can be answered with 304.
+func notModified(ctx context.Context, etag string, lastModified time.Time) bool {
+	method := ctx.Method()
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, etag, true)
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ctx.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// matchETag matches etag against a header list, weak uses the weak comparison function.
+// The content coding suffixes are ignored: If-Match names the resource version whatever coding the client read.
+func matchETag(header, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" {
+			return true
+		}
+		if strings.HasPrefix(item, "W/") {
+			if !weak {
+				continue
+			}
+			item = item[2:]
+		}
+		//同一资源不同压缩编码的ETag只差后缀, 见EncodedETag
+		if identityETag(item) == identityETag(strings.TrimPrefix(etag, "W/")) {
+			return true
+		}
+	}
+	return false
+}
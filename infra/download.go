@@ -0,0 +1,53 @@
+package infra
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/8treenet/dump/infra/export"
+	"github.com/kataras/iris/v12/context"
+)
+
+// ExportResponse streams the rows of Rows as a CSV or XLSX attachment.
+// A failure after the first bytes were sent can only be logged, the download is then truncated.
+type ExportResponse struct {
+	Format   string
+	FileName string
+	Columns  []export.Column
+	Rows     export.Rows
+}
+
+// Dispatch This is the middleware for HTTP output.
+func (erep ExportResponse) Dispatch(ctx context.Context) {
+	if closer, ok := erep.Rows.(interface{ Close() error }); ok {
+		defer closer.Close()
+	}
+	ctx.ContentType(export.ContentTypes[erep.Format])
+	ctx.Header("Content-Disposition", contentDisposition(erep.FileName))
+	count, err := export.Write(erep.Format, ctx.ResponseWriter(), erep.Columns, erep.Rows)
+	if err != nil {
+		ctx.Values().Set("code", "501")
+		ctx.Application().Logger().Errorf("export %s failed after %d rows: %v", erep.FileName, count, err)
+	}
+}
+
+// FileResponse sends a stored file as an attachment named FileName.
+type FileResponse struct {
+	Path        string
+	FileName    string
+	ContentType string
+}
+
+// Dispatch This is the middleware for HTTP output.
+func (frep FileResponse) Dispatch(ctx context.Context) {
+	if frep.ContentType != "" {
+		ctx.ContentType(frep.ContentType)
+	}
+	ctx.Header("Content-Disposition", contentDisposition(frep.FileName))
+	ctx.ServeFile(frep.Path, false)
+}
+
+// contentDisposition encodes non-ASCII file names with RFC 5987, e.g. 订单.xlsx.
+func contentDisposition(fileName string) string {
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, url.PathEscape(fileName), url.PathEscape(fileName))
+}
@@ -0,0 +1,245 @@
+// Package dump exports the registered po tables to SQL, JSON Lines or CSV files and restores them.
+package dump
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/8treenet/dump/infra/masking"
+	"github.com/jinzhu/gorm"
+)
+
+// Formats of the dump files.
+const (
+	FormatSQL   = "sql"
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// Null is the value csv files use for NULL, a value starting with a backslash gets one more.
+const Null = `\N`
+
+var registry []interface{}
+
+// Register adds po objects to the registry. Tables are dumped and restored in registration order,
+// so a table must be registered after the tables it depends on.
+func Register(objects ...interface{}) {
+	registry = append(registry, objects...)
+}
+
+// Tables returns the registered table names in dependency order.
+func Tables() []string {
+	result := []string{}
+	for _, object := range registry {
+		result = append(result, tableName(object))
+	}
+	return result
+}
+
+func tableName(object interface{}) string {
+	if tabler, ok := object.(interface{ TableName() string }); ok {
+		return tabler.TableName()
+	}
+	return reflect.Indirect(reflect.ValueOf(object)).Type().Name()
+}
+
+// lookup returns the registered object of table and its position in the registry.
+func lookup(table string) (interface{}, int, error) {
+	for index, object := range registry {
+		if tableName(object) == table {
+			return object, index, nil
+		}
+	}
+	return nil, -1, fmt.Errorf("table '%s' is not registered", table)
+}
+
+// columns returns the column fields of object in struct order.
+func columns(db *gorm.DB, object interface{}) []*gorm.StructField {
+	result := []*gorm.StructField{}
+	for _, field := range db.NewScope(object).GetModelStruct().StructFields {
+		if field.IsNormal && !field.IsIgnored {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// Dumper .
+type Dumper struct {
+	DB     *gorm.DB
+	Format string
+	// Where filters the rows of a table, the key is the table name and the value a SQL condition.
+	Where map[string]string
+	// ChunkSize is the number of rows read per query, it bounds the memory used by a table.
+	ChunkSize int
+	// Mask applies the masking policies of the po types, e.g. for anonymised staging snapshots.
+	// A dropped column is written with the zero value of its type, so that the dump can still be restored.
+	Mask bool
+	// Location is the zone of the mysql times of a sql dump, the loc of the DSN, UTC by default as for the driver.
+	Location *time.Location
+}
+
+// New .
+func New(db *gorm.DB) *Dumper {
+	return &Dumper{DB: db, Format: FormatSQL, Where: map[string]string{}, ChunkSize: 1000, Location: time.UTC}
+}
+
+// DumpDir writes one NN_table.<format> file per table into dir, NN is the dependency order.
+// All tables are read in a single read-only REPEATABLE READ transaction, so the files are a consistent snapshot.
+// No tables dumps every registered table. It returns the number of rows written per table.
+func (d *Dumper) DumpDir(dir string, tables ...string) (map[string]int, error) {
+	if d.Format != FormatSQL && d.Format != FormatJSONL && d.Format != FormatCSV {
+		return nil, fmt.Errorf("unknown format '%s', expected sql, jsonl or csv", d.Format)
+	}
+	if d.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive")
+	}
+	if len(tables) == 0 {
+		tables = Tables()
+	}
+	indexes := map[string]int{}
+	for _, table := range tables {
+		_, index, err := lookup(table)
+		if err != nil {
+			return nil, err
+		}
+		indexes[table] = index
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		return indexes[tables[i]] < indexes[tables[j]]
+	})
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	tx := d.DB.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer tx.Rollback()
+
+	result := map[string]int{}
+	for _, table := range tables {
+		object, index, _ := lookup(table)
+		file, err := os.Create(filepath.Join(dir, fmt.Sprintf("%02d_%s.%s", index+1, table, d.Format)))
+		if err != nil {
+			return result, err
+		}
+		count, err := d.dump(tx, object, file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return result, fmt.Errorf("%s: %v", table, err)
+		}
+		result[table] = count
+	}
+	return result, nil
+}
+
+// dump writes the rows of object's table to file, reading ChunkSize rows at a time.
+func (d *Dumper) dump(tx *gorm.DB, object interface{}, file *os.File) (count int, err error) {
+	table := tableName(object)
+	policy := map[string]string{}
+	if d.Mask {
+		policy = masking.Policy(object)
+	}
+	fields := []*gorm.StructField{}
+	names := []string{}
+	for _, field := range columns(tx, object) {
+		fields = append(fields, field)
+		names = append(names, field.DBName)
+	}
+	location := d.Location
+	if location == nil {
+		location = time.UTC
+	}
+	buf := bufio.NewWriter(file)
+	out := newRowWriter(d.Format, tx.Dialect(), location, table, names, buf)
+
+	//单主键时按主键分块读取, 否则按第一列排序后 offset 分块。
+	scope := tx.NewScope(object)
+	quote := tx.Dialect().Quote
+	primary := -1
+	if len(scope.PrimaryFields()) == 1 {
+		for index, field := range fields {
+			if field.DBName == scope.PrimaryField().DBName {
+				primary = index
+			}
+		}
+	}
+	sliceType := reflect.SliceOf(reflect.TypeOf(object))
+	var last interface{}
+	for {
+		query := tx
+		if where := d.Where[table]; where != "" {
+			query = query.Where(where)
+		}
+		if primary >= 0 {
+			if last != nil {
+				query = query.Where(quote(names[primary])+" > ?", last)
+			}
+			query = query.Order(quote(names[primary]))
+		} else {
+			query = query.Order(quote(names[0])).Offset(count)
+		}
+		rows := reflect.New(sliceType)
+		if err = query.Limit(d.ChunkSize).Find(rows.Interface()).Error; err != nil {
+			return
+		}
+
+		slice := rows.Elem()
+		for index := 0; index < slice.Len(); index++ {
+			item := reflect.Indirect(slice.Index(index))
+			values := make([]interface{}, len(fields))
+			for column, field := range fields {
+				values[column] = fieldValue(item.FieldByName(field.Name))
+			}
+			if primary >= 0 {
+				last = values[primary]
+			}
+			for column, field := range fields {
+				action, ok := policy[field.DBName]
+				if !ok {
+					continue
+				}
+				if action == masking.Drop {
+					//删除的列写入零值, NOT NULL 列仍可恢复
+					values[column] = fieldValue(reflect.Zero(field.Struct.Type))
+					continue
+				}
+				values[column], _ = masking.Apply(action, field.DBName, values[column])
+			}
+			if err = out.write(values); err != nil {
+				return
+			}
+			count++
+		}
+		if slice.Len() < d.ChunkSize {
+			break
+		}
+	}
+	if err = out.close(); err != nil {
+		return
+	}
+	err = buf.Flush()
+	return
+}
+
+// fieldValue returns the value of a column field, nil for a nil pointer.
+func fieldValue(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	return value.Interface()
+}
@@ -0,0 +1,246 @@
+package dump
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/8treenet/dump/infra/masking"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+type dumpParent struct {
+	ID   int `gorm:"primary_key"`
+	Name string
+}
+
+func (dumpParent) TableName() string { return "dump_parents" }
+
+type dumpRow struct {
+	ID       int `gorm:"primary_key"`
+	ParentID int
+	Text     string
+	Nullable *string
+	Payload  []byte
+	Flag     bool
+	Price    float64
+	Created  time.Time
+	Deleted  *time.Time
+}
+
+func (dumpRow) TableName() string { return "dump_rows" }
+
+// TestRoundTrip dumps and restores the rows in every format, with NULLs, a literal \N,
+// binary values and times of another zone.
+func TestRoundTrip(t *testing.T) {
+	registry = nil
+	Register(&dumpParent{}, &dumpRow{})
+
+	zone := time.FixedZone("UTC+8", 8*3600)
+	literal := Null
+	created := time.Date(2020, 5, 1, 10, 30, 0, 123456000, zone)
+	rows := []dumpRow{
+		{ID: 1, ParentID: 1, Text: `\N`, Nullable: &literal, Payload: []byte{0, 1, 2, 0xff}, Flag: true, Price: 9.5, Created: created, Deleted: &created},
+		{ID: 2, ParentID: 1, Text: `\\N, "quoted"` + "\n'line'", Payload: []byte{}, Created: created.UTC()},
+	}
+
+	for _, format := range []string{FormatSQL, FormatJSONL, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			db := openDB(t, format)
+			defer db.Close()
+			if err := db.Create(&dumpParent{ID: 1, Name: "parent"}).Error; err != nil {
+				t.Fatal(err)
+			}
+			for index := range rows {
+				if err := db.Create(&rows[index]).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dir, err := ioutil.TempDir("", "dump")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			dumper := New(db)
+			dumper.Format = format
+			if _, err := dumper.DumpDir(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			restorer := NewRestorer(db)
+			restorer.Truncate = true
+			counts, err := restorer.RestoreDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if counts["dump_rows"] != len(rows) || counts["dump_parents"] != 1 {
+				t.Fatalf("unexpected counts %v", counts)
+			}
+
+			restored := []dumpRow{}
+			if err := db.Order("id").Find(&restored).Error; err != nil {
+				t.Fatal(err)
+			}
+			if len(restored) != len(rows) {
+				t.Fatalf("restored %d rows, expected %d", len(restored), len(rows))
+			}
+			for index, row := range restored {
+				checkRow(t, row, rows[index])
+			}
+		})
+	}
+}
+
+// TestRestoreRollback checks that a failed restore keeps the truncated rows.
+func TestRestoreRollback(t *testing.T) {
+	registry = nil
+	Register(&dumpParent{}, &dumpRow{})
+
+	db := openDB(t, "rollback")
+	defer db.Close()
+	if err := db.Create(&dumpParent{ID: 1, Name: "parent"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(dir+"/02_dump_rows.jsonl", []byte("{\"unknown\": 1}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(dir+"/01_dump_parents.jsonl", []byte("{\"id\": 2, \"name\": \"new\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restorer := NewRestorer(db)
+	restorer.Truncate = true
+	if _, err := restorer.RestoreDir(dir); err == nil {
+		t.Fatal("expected the unknown column to fail the restore")
+	}
+	parents := []dumpParent{}
+	if err := db.Find(&parents).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) != 1 || parents[0].Name != "parent" {
+		t.Fatalf("the failed restore changed the rows: %v", parents)
+	}
+}
+
+type dumpSecret struct {
+	ID       int    `gorm:"primary_key"`
+	Password string `gorm:"not null"`
+}
+
+func (dumpSecret) TableName() string { return "dump_secrets" }
+
+func (dumpSecret) MaskingPolicy() map[string]string {
+	return map[string]string{"password": masking.Drop}
+}
+
+// TestMaskedRestore checks that a dropped NOT NULL column is dumped as its zero value, so that the dump restores.
+func TestMaskedRestore(t *testing.T) {
+	registry = nil
+	Register(&dumpSecret{})
+
+	db := openDB(t, "masked")
+	defer db.Close()
+	if err := db.AutoMigrate(&dumpSecret{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&dumpSecret{ID: 1, Password: "secret"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{FormatSQL, FormatJSONL, FormatCSV} {
+		dir, err := ioutil.TempDir("", "dump")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		dumper := New(db)
+		dumper.Format = format
+		dumper.Mask = true
+		if _, err := dumper.DumpDir(dir); err != nil {
+			t.Fatal(err)
+		}
+		restorer := NewRestorer(db)
+		restorer.Truncate = true
+		if _, err := restorer.RestoreDir(dir); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		secret := dumpSecret{}
+		if err := db.First(&secret, 1).Error; err != nil || secret.Password != "" {
+			t.Fatalf("%s: restored %+v, %v", format, secret, err)
+		}
+	}
+}
+
+// TestMySQLTime checks that the mysql times are written in the zone of the connection without an offset,
+// which mysql before 8.0.19 rejects.
+func TestMySQLTime(t *testing.T) {
+	created := time.Date(2020, 5, 1, 10, 30, 0, 123456000, time.FixedZone("UTC+8", 8*3600))
+	cases := []struct {
+		dialect  string
+		location *time.Location
+		expected string
+	}{
+		{"mysql", time.UTC, "'2020-05-01 02:30:00.123456'"},
+		{"mysql", time.FixedZone("UTC-2", -2*3600), "'2020-05-01 00:30:00.123456'"},
+		{"postgres", time.UTC, "'2020-05-01 10:30:00.123456+08:00'"},
+	}
+	for _, item := range cases {
+		if literal := sqlLiteral(item.dialect, item.location, created); literal != item.expected {
+			t.Errorf("%s in %s: %s, expected %s", item.dialect, item.location, literal, item.expected)
+		}
+	}
+}
+
+func openDB(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open("sqlite3", fmt.Sprintf("file:dump_%s?mode=memory&cache=shared", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//内存数据库在最后一个连接关闭时销毁
+	db.DB().SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&dumpParent{}, &dumpRow{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func checkRow(t *testing.T, got, expected dumpRow) {
+	t.Helper()
+	if got.Text != expected.Text {
+		t.Errorf("row %d: text %q, expected %q", expected.ID, got.Text, expected.Text)
+	}
+	if (got.Nullable == nil) != (expected.Nullable == nil) || (got.Nullable != nil && *got.Nullable != *expected.Nullable) {
+		t.Errorf("row %d: nullable %v, expected %v", expected.ID, got.Nullable, expected.Nullable)
+	}
+	if len(got.Payload) != len(expected.Payload) || (len(got.Payload) > 0 && !reflect.DeepEqual(got.Payload, expected.Payload)) {
+		t.Errorf("row %d: payload %v, expected %v", expected.ID, got.Payload, expected.Payload)
+	}
+	if got.Flag != expected.Flag || got.Price != expected.Price || got.ParentID != expected.ParentID {
+		t.Errorf("row %d: unexpected %+v", expected.ID, got)
+	}
+	checkTime(t, expected.ID, got.Created, expected.Created)
+	if (got.Deleted == nil) != (expected.Deleted == nil) {
+		t.Errorf("row %d: deleted %v, expected %v", expected.ID, got.Deleted, expected.Deleted)
+	} else if got.Deleted != nil {
+		checkTime(t, expected.ID, *got.Deleted, *expected.Deleted)
+	}
+}
+
+// checkTime compares the instant and the zone offset.
+func checkTime(t *testing.T, id int, got, expected time.Time) {
+	t.Helper()
+	_, gotOffset := got.Zone()
+	_, expectedOffset := expected.Zone()
+	if !got.Equal(expected) || gotOffset != expectedOffset {
+		t.Errorf("row %d: time %v, expected %v", id, got, expected)
+	}
+}
@@ -0,0 +1,181 @@
+package dump
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// rowWriter writes the rows of one table in a dump format.
+type rowWriter interface {
+	write(values []interface{}) error
+	close() error
+}
+
+func newRowWriter(format string, dialect gorm.Dialect, location *time.Location, table string, columns []string, w *bufio.Writer) rowWriter {
+	switch format {
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &jsonlWriter{encoder: encoder, columns: columns}
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w), columns: columns}
+	}
+	quoted := []string{}
+	for _, column := range columns {
+		quoted = append(quoted, dialect.Quote(column))
+	}
+	fmt.Fprintf(w, "-- dump of table %s\n", table)
+	return &sqlWriter{
+		w:        w,
+		dialect:  dialect.GetName(),
+		location: location,
+		prefix:   fmt.Sprintf("INSERT INTO %s (%s) VALUES (", dialect.Quote(table), strings.Join(quoted, ",")),
+	}
+}
+
+// sqlWriter writes one INSERT statement per row.
+type sqlWriter struct {
+	w        *bufio.Writer
+	dialect  string
+	location *time.Location
+	prefix   string
+}
+
+func (s *sqlWriter) write(values []interface{}) error {
+	literals := []string{}
+	for _, value := range values {
+		literals = append(literals, sqlLiteral(s.dialect, s.location, value))
+	}
+	_, err := fmt.Fprintf(s.w, "%s%s);\n", s.prefix, strings.Join(literals, ","))
+	return err
+}
+
+func (s *sqlWriter) close() error {
+	return nil
+}
+
+const (
+	// sqlTime keeps the zone offset of the times, for postgres and sqlite.
+	sqlTime = "2006-01-02 15:04:05.999999-07:00"
+	// sqlLocalTime is a mysql DATETIME, which has no zone, mysql only accepts an offset since 8.0.19.
+	sqlLocalTime = "2006-01-02 15:04:05.999999"
+)
+
+// sqlLiteral formats value as a SQL literal of dialect, mysql also escapes backslashes
+// and gets the times in location, the zone the driver reads them in.
+func sqlLiteral(dialect string, location *time.Location, value interface{}) string {
+	quote := func(str string) string {
+		if dialect == "mysql" {
+			str = strings.Replace(str, `\`, `\\`, -1)
+		}
+		return "'" + strings.Replace(str, "'", "''", -1) + "'"
+	}
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quote(value)
+	case time.Time:
+		if dialect == "mysql" {
+			return quote(value.In(location).Format(sqlLocalTime))
+		}
+		return quote(value.Format(sqlTime))
+	case bool:
+		if dialect == "postgres" {
+			return fmt.Sprint(value)
+		}
+		if value {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		if dialect == "postgres" {
+			return `'\x` + hex.EncodeToString(value) + "'::bytea"
+		}
+		return "X'" + hex.EncodeToString(value) + "'"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(value)
+	}
+	return quote(fmt.Sprint(value))
+}
+
+// jsonlWriter writes one JSON object per row, keyed by column name.
+type jsonlWriter struct {
+	encoder *json.Encoder
+	columns []string
+}
+
+func (j *jsonlWriter) write(values []interface{}) error {
+	row := map[string]interface{}{}
+	for index, column := range j.columns {
+		row[column] = values[index]
+	}
+	return j.encoder.Encode(row)
+}
+
+func (j *jsonlWriter) close() error {
+	return nil
+}
+
+// csvWriter writes a header line with the column names and one record per row.
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []string
+	header  bool
+}
+
+func (c *csvWriter) write(values []interface{}) error {
+	if !c.header {
+		c.header = true
+		if err := c.writer.Write(c.columns); err != nil {
+			return err
+		}
+	}
+	record := []string{}
+	for _, value := range values {
+		switch value := value.(type) {
+		case nil:
+			record = append(record, Null)
+		case time.Time:
+			record = append(record, value.Format(time.RFC3339Nano))
+		case []byte:
+			record = append(record, base64.StdEncoding.EncodeToString(value))
+		default:
+			record = append(record, csvEscape(fmt.Sprint(value)))
+		}
+	}
+	return c.writer.Write(record)
+}
+
+// csvEscape doubles a leading backslash, so a literal \N is written \\N and differs from Null.
+func csvEscape(value string) string {
+	if strings.HasPrefix(value, `\`) {
+		return `\` + value
+	}
+	return value
+}
+
+// csvUnescape reverts csvEscape.
+func csvUnescape(value string) string {
+	if strings.HasPrefix(value, `\`) {
+		return value[1:]
+	}
+	return value
+}
+
+func (c *csvWriter) close() error {
+	if !c.header {
+		c.header = true
+		c.writer.Write(c.columns)
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
@@ -0,0 +1,325 @@
+package dump
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/8treenet/dump/infra/fixture"
+	"github.com/jinzhu/gorm"
+)
+
+// Restorer .
+type Restorer struct {
+	DB *gorm.DB
+	// Truncate deletes the rows of every restored table first, in reverse dependency order.
+	Truncate bool
+	// BatchSize is the number of jsonl or csv rows held in memory before they are inserted.
+	BatchSize int
+}
+
+// NewRestorer .
+func NewRestorer(db *gorm.DB) *Restorer {
+	return &Restorer{DB: db, BatchSize: 500}
+}
+
+var fileName = regexp.MustCompile(`^(?:\d+_)?(\w+)\.(sql|jsonl|csv)$`)
+
+// RestoreDir replays the dump files of dir in dependency order, the truncation and all tables in one transaction.
+// It returns the number of rows restored per table.
+func (r *Restorer) RestoreDir(dir string) (map[string]int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	tables := []string{}
+	paths := map[string]string{}
+	indexes := map[string]int{}
+	for _, file := range files {
+		match := fileName.FindStringSubmatch(file.Name())
+		if file.IsDir() || match == nil {
+			continue
+		}
+		table := match[1]
+		if _, ok := paths[table]; ok {
+			return nil, fmt.Errorf("%s: more than one dump file of table '%s'", file.Name(), table)
+		}
+		_, index, err := lookup(table)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file.Name(), err)
+		}
+		tables = append(tables, table)
+		paths[table] = filepath.Join(dir, file.Name())
+		indexes[table] = index
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return indexes[tables[i]] < indexes[tables[j]]
+	})
+
+	tx := r.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer tx.Rollback()
+	if r.Truncate {
+		//先删除依赖方的数据, 逆序清空。
+		for index := len(tables) - 1; index >= 0; index-- {
+			if err := tx.Exec("DELETE FROM " + tx.Dialect().Quote(tables[index])).Error; err != nil {
+				return nil, fmt.Errorf("%s: %v", tables[index], err)
+			}
+		}
+	}
+
+	result := map[string]int{}
+	for _, table := range tables {
+		count, err := r.restoreFile(tx, table, paths[table])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Base(paths[table]), err)
+		}
+		result[table] = count
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RestoreFile replays one sql, jsonl or csv dump file into table in a single transaction.
+func (r *Restorer) RestoreFile(table, file string) (int, error) {
+	tx := r.DB.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	defer tx.Rollback()
+	count, err := r.restoreFile(tx, table, file)
+	if err != nil {
+		return 0, err
+	}
+	return count, tx.Commit().Error
+}
+
+func (r *Restorer) restoreFile(tx *gorm.DB, table, file string) (int, error) {
+	object, _, err := lookup(table)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var count int
+	switch filepath.Ext(file) {
+	case "." + FormatSQL:
+		count, err = r.restoreSQL(tx, bufio.NewReader(f))
+	case "." + FormatJSONL:
+		count, err = r.restoreRows(tx, object, jsonlReader(f))
+	case "." + FormatCSV:
+		count, err = r.restoreRows(tx, object, csvReader(f))
+	default:
+		return 0, fmt.Errorf("unknown dump format '%s'", filepath.Ext(file))
+	}
+	if err != nil {
+		return count, err
+	}
+	return count, resetSequence(tx, object)
+}
+
+// resetSequence moves the serial sequence of a postgres table past the restored ids,
+// the explicit ids of the dump don't advance it. Tables without a serial primary key are left as they are.
+func resetSequence(tx *gorm.DB, object interface{}) error {
+	if tx.Dialect().GetName() != "postgres" {
+		return nil
+	}
+	scope := tx.NewScope(object)
+	if len(scope.PrimaryFields()) != 1 {
+		return nil
+	}
+	quote := tx.Dialect().Quote
+	table, column := tableName(object), scope.PrimaryField().DBName
+	//pg_get_serial_sequence 对非serial列返回NULL, setval(NULL, ...) 不做任何事
+	return tx.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(%s), 1), MAX(%s) IS NOT NULL) FROM %s",
+		quote(column), quote(column), quote(table)), quote(table), column).Error
+}
+
+// restoreSQL executes the statements of a sql dump one by one.
+func (r *Restorer) restoreSQL(tx *gorm.DB, reader *bufio.Reader) (int, error) {
+	backslash := tx.Dialect().GetName() == "mysql"
+	count := 0
+	for {
+		statement, err := readStatement(reader, backslash)
+		if err != nil && err != io.EOF {
+			return count, err
+		}
+		if statement != "" {
+			if _, execErr := tx.CommonDB().Exec(statement); execErr != nil {
+				return count, fmt.Errorf("statement %d: %v", count+1, execErr)
+			}
+			count++
+		}
+		if err == io.EOF {
+			return count, nil
+		}
+	}
+}
+
+// readStatement reads up to the next ';' outside a string literal, skipping '--' comment lines.
+func readStatement(reader *bufio.Reader, backslash bool) (string, error) {
+	var builder strings.Builder
+	quoted, escaped, lineStart := false, false, true
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return strings.TrimSpace(builder.String()), err
+		}
+		if !quoted && lineStart && c == '-' {
+			if next, _ := reader.Peek(1); len(next) == 1 && next[0] == '-' {
+				if _, err := reader.ReadString('\n'); err != nil {
+					return strings.TrimSpace(builder.String()), err
+				}
+				continue
+			}
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && backslash && c == '\\':
+			escaped = true
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && c == ';':
+			return strings.TrimSpace(builder.String()), nil
+		}
+		lineStart = c == '\n'
+		builder.WriteByte(c)
+	}
+}
+
+// rowReader returns the next row of a jsonl or csv dump, io.EOF after the last one.
+type rowReader func() (map[string]interface{}, error)
+
+func jsonlReader(r io.Reader) rowReader {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return func() (map[string]interface{}, error) {
+		row := map[string]interface{}{}
+		err := decoder.Decode(&row)
+		return row, err
+	}
+}
+
+func csvReader(r io.Reader) rowReader {
+	reader := csv.NewReader(r)
+	var header []string
+	return func() (map[string]interface{}, error) {
+		if header == nil {
+			var err error
+			if header, err = reader.Read(); err != nil {
+				return nil, err
+			}
+		}
+		record, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		row := map[string]interface{}{}
+		for index, column := range header {
+			if record[index] == Null {
+				row[column] = nil
+				continue
+			}
+			row[column] = csvUnescape(record[index])
+		}
+		return row, nil
+	}
+}
+
+// restoreRows converts the rows to the column types of object and inserts them BatchSize at a time.
+func (r *Restorer) restoreRows(tx *gorm.DB, object interface{}, next rowReader) (int, error) {
+	table := tableName(object)
+	types := map[string]reflect.Type{}
+	for _, field := range columns(tx, object) {
+		types[field.DBName] = field.Struct.Type
+	}
+
+	count := 0
+	batch := []map[string]interface{}{}
+	for {
+		row, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("row %d: %v", count+len(batch)+1, err)
+		}
+		for column, value := range row {
+			typ, ok := types[column]
+			if !ok {
+				return count, fmt.Errorf("row %d: unknown column '%s'", count+len(batch)+1, column)
+			}
+			if row[column], err = convert(value, typ); err != nil {
+				return count, fmt.Errorf("row %d, column %s: %v", count+len(batch)+1, column, err)
+			}
+		}
+		if batch = append(batch, row); len(batch) >= r.BatchSize {
+			if err := fixture.Insert(tx, table, batch); err != nil {
+				return count, err
+			}
+			count += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := fixture.Insert(tx, table, batch); err != nil {
+		return count, err
+	}
+	return count + len(batch), nil
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte{})
+)
+
+// convert converts a decoded jsonl or csv value to the Go type of its column.
+func convert(value interface{}, typ reflect.Type) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	text := fmt.Sprint(value)
+	if typ == timeType {
+		return time.Parse(time.RFC3339Nano, text)
+	}
+	if typ == bytesType {
+		//jsonl和csv中的[]byte是base64编码
+		return base64.StdEncoding.DecodeString(text)
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(text, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(text, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(text, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(text)
+	case reflect.String:
+		return text, nil
+	}
+	return value, nil
+}
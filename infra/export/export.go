@@ -0,0 +1,163 @@
+// Package export writes po rows as CSV or XLSX spreadsheets with localized headers.
+package export
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/8treenet/dump/infra/masking"
+)
+
+// Formats of an export.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ContentTypes of the formats.
+var ContentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// DefaultHeaders are the headers of the common columns without a comment tag.
+var DefaultHeaders = map[string]string{
+	"id":      "编号",
+	"created": "创建时间",
+	"updated": "更新时间",
+}
+
+// TimeLayout formats time columns.
+const TimeLayout = "2006-01-02 15:04:05"
+
+// Rows yields rows one by one, e.g. repository.BatchIterator.
+type Rows interface {
+	Next() (row interface{}, ok bool, err error)
+}
+
+// Column is an exported column of a po type.
+type Column struct {
+	Name   string // 数据库列名
+	Header string // 表头, 取 po 字段的 comment 标签
+	field  string
+	action string
+}
+
+// Columns returns the exportable columns of a po object, all of them when selected is empty.
+// Columns dropped by the masking policy are never exported.
+func Columns(object interface{}, selected ...string) ([]Column, error) {
+	t := reflect.TypeOf(object)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	policy := masking.Policy(object)
+	all := []Column{}
+	byName := map[string]Column{}
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		name := columnName(field)
+		if field.PkgPath != "" || name == "" || policy[name] == masking.Drop {
+			continue
+		}
+		header := field.Tag.Get("comment")
+		if header == "" {
+			header = DefaultHeaders[name]
+		}
+		if header == "" {
+			header = name
+		}
+		column := Column{Name: name, Header: header, field: field.Name, action: policy[name]}
+		all = append(all, column)
+		byName[name] = column
+	}
+	if len(selected) == 0 {
+		return all, nil
+	}
+
+	result := []Column{}
+	for _, name := range selected {
+		column, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column '%s'", name)
+		}
+		result = append(result, column)
+	}
+	return result, nil
+}
+
+func columnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(setting, "column:") {
+			return strings.TrimPrefix(setting, "column:")
+		}
+	}
+	return ""
+}
+
+// values returns the formatted, masked cells of a row.
+func values(columns []Column, row interface{}) []interface{} {
+	item := reflect.Indirect(reflect.ValueOf(row))
+	result := make([]interface{}, len(columns))
+	for index, column := range columns {
+		value := item.FieldByName(column.field)
+		var cell interface{}
+		if value.Kind() != reflect.Ptr || !value.IsNil() {
+			cell = reflect.Indirect(value).Interface()
+		}
+		if column.action != "" {
+			cell, _ = masking.Apply(column.action, column.Name, cell)
+		}
+		if t, ok := cell.(time.Time); ok {
+			cell = ""
+			if !t.IsZero() {
+				cell = t.Format(TimeLayout)
+			}
+		}
+		result[index] = cell
+	}
+	return result
+}
+
+// Writer writes the rows of an export.
+type Writer interface {
+	Write(row interface{}) error
+	Close() error
+}
+
+// NewWriter writes the header line and returns the writer of the rows.
+func NewWriter(format string, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	}
+	return nil, fmt.Errorf("unknown format '%s', expected csv or xlsx", format)
+}
+
+// Write writes every row of rows and returns the number of rows written.
+func Write(format string, w io.Writer, columns []Column, rows Rows) (count int, err error) {
+	writer, err := NewWriter(format, w, columns)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		row, ok, err := rows.Next()
+		if err != nil {
+			writer.Close()
+			return count, err
+		}
+		if !ok {
+			break
+		}
+		if err := writer.Write(row); err != nil {
+			writer.Close()
+			return count, err
+		}
+		count++
+	}
+	return count, writer.Close()
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Statuses of a Job.
+const (
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+var (
+	// Dir stores the files of the background exports.
+	Dir = filepath.Join(os.TempDir(), "dump-exports")
+	// Retention is how long finished export files are kept.
+	Retention = 24 * time.Hour
+)
+
+// Job is an export running in the background, its file is stored in the export directory.
+type Job struct {
+	ID       string    `json:"id"`
+	Status   string    `json:"status"`
+	FileName string    `json:"fileName"`
+	Rows     int       `json:"rows"`
+	Error    string    `json:"error,omitempty"`
+	Created  time.Time `json:"created"`
+	Finished time.Time `json:"finished,omitempty"`
+	path     string
+}
+
+// Path returns the stored file of a finished job.
+func (j *Job) Path() string {
+	return j.path
+}
+
+var (
+	jobsMu sync.Mutex
+	jobs   = map[string]*Job{}
+)
+
+// Start runs write in the background into a new file of Dir, fileName is the name of the download.
+// The returned job is a snapshot, use Lookup for the current state.
+func Start(fileName string, write func(w io.Writer) (int, error)) (Job, error) {
+	cleanup()
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		return Job{}, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Job{}, err
+	}
+	job := &Job{
+		ID:       hex.EncodeToString(id),
+		Status:   StatusRunning,
+		FileName: fileName,
+		Created:  time.Now(),
+	}
+	job.path = filepath.Join(Dir, job.ID+filepath.Ext(fileName))
+	file, err := os.Create(job.path)
+	if err != nil {
+		return Job{}, err
+	}
+
+	jobsMu.Lock()
+	jobs[job.ID] = job
+	snapshot := *job
+	jobsMu.Unlock()
+
+	go func() {
+		var rows int
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("export panic: %v", r)
+			}
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			jobsMu.Lock()
+			defer jobsMu.Unlock()
+			job.Rows, job.Finished, job.Status = rows, time.Now(), StatusDone
+			if err != nil {
+				job.Status, job.Error = StatusFailed, err.Error()
+				os.Remove(job.path)
+			}
+		}()
+		rows, err = write(file)
+	}()
+	return snapshot, nil
+}
+
+// Lookup returns a snapshot of the job with id.
+func Lookup(id string) (Job, bool) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	job, ok := jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// cleanup forgets the jobs finished longer than Retention ago and deletes their files.
+func cleanup() {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	for id, job := range jobs {
+		if job.Status != StatusRunning && time.Since(job.Finished) > Retention {
+			os.Remove(job.path)
+			delete(jobs, id)
+		}
+	}
+}
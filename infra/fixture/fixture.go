@@ -0,0 +1,123 @@
+// Package fixture loads YAML and JSON fixtures into any po table.
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"gopkg.in/yaml.v3"
+)
+
+// Loader .
+type Loader struct {
+	DB *gorm.DB
+	// Truncate deletes the existing rows of a table before loading its fixture.
+	Truncate bool
+}
+
+// New .
+func New(db *gorm.DB) *Loader {
+	return &Loader{DB: db}
+}
+
+var fileName = regexp.MustCompile(`^(?:\d+_)?(\w+)\.(yml|yaml|json)$`)
+
+// LoadDir loads every <table>.yml, <table>.yaml and <table>.json file of dir in file name order,
+// a numeric prefix orders dependent tables, e.g. 01_user.yml before 02_order.yml.
+// It returns the number of rows inserted per table.
+func (l *Loader) LoadDir(dir string) (map[string]int, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, file := range files {
+		if !file.IsDir() && fileName.MatchString(file.Name()) {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	result := map[string]int{}
+	for _, name := range names {
+		table := fileName.FindStringSubmatch(name)[1]
+		count, err := l.LoadFile(table, filepath.Join(dir, name))
+		if err != nil {
+			return result, fmt.Errorf("%s: %v", name, err)
+		}
+		result[table] += count
+	}
+	return result, nil
+}
+
+// LoadFile loads the rows of a YAML or JSON file, a list of column to value maps, into table.
+func (l *Loader) LoadFile(table, file string) (int, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	rows := []map[string]interface{}{}
+	if strings.HasSuffix(file, ".json") {
+		err = json.Unmarshal(content, &rows)
+	} else {
+		err = yaml.Unmarshal(content, &rows)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), l.Load(table, rows)
+}
+
+// Load inserts rows into table in a single transaction.
+func (l *Loader) Load(table string, rows []map[string]interface{}) error {
+	tx := l.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if err := l.load(tx, table, rows); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+func (l *Loader) load(tx *gorm.DB, table string, rows []map[string]interface{}) error {
+	if l.Truncate {
+		if err := tx.Exec("DELETE FROM " + tx.Dialect().Quote(table)).Error; err != nil {
+			return err
+		}
+	}
+	return Insert(tx, table, rows)
+}
+
+// Insert inserts rows, column to value maps, into table with one statement per row.
+func Insert(db *gorm.DB, table string, rows []map[string]interface{}) error {
+	quote := db.Dialect().Quote
+	for _, row := range rows {
+		columns := []string{}
+		for column := range row {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+
+		quoted := []string{}
+		placeholders := []string{}
+		values := []interface{}{}
+		for _, column := range columns {
+			quoted = append(quoted, quote(column))
+			placeholders = append(placeholders, "?")
+			values = append(values, row[column])
+		}
+		statement := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quote(table), strings.Join(quoted, ","), strings.Join(placeholders, ","))
+		if err := db.Exec(statement, values...).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
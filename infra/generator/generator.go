@@ -0,0 +1,113 @@
+// Package generator (re)generates the po structs and the repository helpers from a database schema.
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Generator .
+type Generator struct {
+	// PODir is the directory of the po package, e.g. domain/po.
+	PODir string
+	// RepositoryFile is the file of the generated repository helpers, e.g. adapter/repository/generate.go.
+	RepositoryFile string
+	// Check reports stale files instead of writing them.
+	Check bool
+}
+
+// Generate renders every table and writes the files whose content changed.
+// It returns the changed files, which are the stale files in Check mode.
+// A column without a comment in the schema, e.g. of SQLite which has none, keeps the comment of its current po field,
+// so that the output doesn't depend on the dialect.
+func (g *Generator) Generate(tables []*Table) (changed []string, err error) {
+	files := map[string][]byte{}
+	for _, table := range tables {
+		name := filepath.Join(g.PODir, table.Name+".go")
+		comments, err := currentComments(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range table.Columns {
+			if column.Comment == "" {
+				column.Comment = comments[column.Name]
+			}
+		}
+		content, err := RenderPO(table)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = content
+	}
+	content, err := RenderRepository(tables)
+	if err != nil {
+		return nil, err
+	}
+	files[g.RepositoryFile] = content
+
+	for _, name := range sortedKeys(files) {
+		current, err := ioutil.ReadFile(name)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if bytes.Equal(current, files[name]) {
+			continue
+		}
+		changed = append(changed, name)
+		if g.Check {
+			continue
+		}
+		if err := ioutil.WriteFile(name, files[name], 0644); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	result := []string{}
+	for name := range files {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// currentComments returns the comment tags of the fields of a po file by column, none when the file doesn't exist.
+func currentComments(name string) (map[string]string, error) {
+	result := map[string]string{}
+	file, err := parser.ParseFile(token.NewFileSet(), name, nil, 0)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		field, ok := node.(*ast.Field)
+		if !ok || field.Tag == nil {
+			return true
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return true
+		}
+		structTag := reflect.StructTag(tag)
+		for _, option := range strings.Split(structTag.Get("gorm"), ";") {
+			if strings.HasPrefix(option, "column:") && structTag.Get("comment") != "" {
+				result[strings.TrimPrefix(option, "column:")] = structTag.Get("comment")
+			}
+		}
+		return true
+	})
+	return result, nil
+}
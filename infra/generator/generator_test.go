@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"testing"
+)
+
+// TestCheck checks that the committed po files and repository helpers are up to date with the migrations
+// of every dialect, e.g. that the comments of the MySQL and Postgres migrations survive SQLite.
+func TestCheck(t *testing.T) {
+	for _, dialect := range []string{"mysql", "postgres", "sqlite3"} {
+		t.Run(dialect, func(t *testing.T) {
+			tables, err := FromMigrations("../../server/migrations", dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g := &Generator{PODir: "../../domain/po", RepositoryFile: "../../adapter/repository/generate.go", Check: true}
+			changed, err := g.Generate(tables)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range changed {
+				t.Errorf("stale %s, run 'server generate -from migrations -dialect %s'", name, dialect)
+			}
+		})
+	}
+}
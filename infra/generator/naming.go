@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names, e.g. user_id -> UserID.
+var initialisms = map[string]bool{
+	"id": true, "ip": true, "url": true, "uri": true, "api": true,
+	"http": true, "json": true, "sql": true, "uuid": true,
+}
+
+// words splits a snake_case or CamelCase name into lower case words.
+func words(name string) []string {
+	result := []string{}
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	}) {
+		runes := []rune(part)
+		start := 0
+		for index := 1; index < len(runes); index++ {
+			if unicode.IsUpper(runes[index]) && !unicode.IsUpper(runes[index-1]) {
+				result = append(result, strings.ToLower(string(runes[start:index])))
+				start = index
+			}
+		}
+		result = append(result, strings.ToLower(string(runes[start:])))
+	}
+	return result
+}
+
+// goName returns the exported Go name of a table or column, e.g. order_detail -> OrderDetail, AlbumId -> AlbumID.
+func goName(name string) string {
+	var builder strings.Builder
+	for _, word := range words(name) {
+		if initialisms[word] {
+			builder.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
+
+// lowerCamel returns the unexported form of goName, e.g. UserID -> userID, ID -> id.
+func lowerCamel(name string) string {
+	list := words(name)
+	if len(list) == 0 {
+		return ""
+	}
+	first := goName(list[0])
+	return strings.ToLower(first) + strings.TrimPrefix(goName(name), first)
+}
+
+// paramName returns lowerCamel, avoiding Go keywords, e.g. type -> typeValue.
+func paramName(name string) string {
+	result := lowerCamel(name)
+	if token.IsKeyword(result) {
+		result += "Value"
+	}
+	return result
+}
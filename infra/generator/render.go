@@ -0,0 +1,395 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// field is a column mapped to a po struct field.
+type field struct {
+	*Column
+	Name     string // Go field name
+	Param    string // setter parameter name
+	Type     string // Go type of the field
+	JSON     string
+	Addable  bool
+	Nullable bool
+}
+
+type entity struct {
+	Table   *Table
+	Name    string
+	Fields  []*field
+	Imports []string
+}
+
+// goType maps a database type to a Go type.
+func goType(dbType string) string {
+	dbType = strings.ToLower(dbType)
+	base := dbType
+	if pos := strings.IndexAny(base, "( "); pos >= 0 {
+		base = base[:pos]
+	}
+	switch base {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "serial", "bigserial", "smallserial":
+		return "int"
+	case "float", "double", "real", "decimal", "numeric", "double precision":
+		return "float64"
+	case "bool", "boolean":
+		return "bool"
+	case "date", "datetime", "timestamp", "timestamptz":
+		return "time.Time"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		return "[]byte"
+	}
+	if strings.HasPrefix(dbType, "timestamp") {
+		return "time.Time"
+	}
+	return "string"
+}
+
+func newEntity(table *Table) *entity {
+	result := &entity{Table: table, Name: goName(table.Name)}
+	imports := map[string]bool{}
+	for _, column := range table.Columns {
+		item := &field{
+			Column: column,
+			Name:   goName(column.Name),
+			Param:  paramName(column.Name),
+			Type:   goType(column.Type),
+		}
+		item.JSON = lowerCamel(column.Name)
+		item.Nullable = column.Nullable && !column.PrimaryKey
+		item.Addable = !column.PrimaryKey && !item.Nullable && (item.Type == "int" || item.Type == "float64")
+		if item.Type == "time.Time" {
+			imports["time"] = true
+		}
+		if item.Addable {
+			imports["github.com/jinzhu/gorm"] = true
+		}
+		result.Fields = append(result.Fields, item)
+	}
+	for name := range imports {
+		result.Imports = append(result.Imports, name)
+	}
+	sort.Strings(result.Imports)
+	return result
+}
+
+// FieldType returns the declared type of a field.
+// Nullable columns are pointers, so that NULL scans into nil and is marshalled as JSON null.
+func (f *field) FieldType() string {
+	if f.Nullable {
+		return "*" + f.Type
+	}
+	return f.Type
+}
+
+// Tag returns the struct tag of a field.
+func (f *field) Tag() string {
+	gormTag := "column:" + f.Column.Name
+	if f.PrimaryKey {
+		gormTag = "primary_key;" + gormTag
+	}
+	tag := "gorm:\"" + gormTag + "\" json:\"" + f.JSON + "\""
+	if comment := f.singleLineComment(); comment != "" {
+		//导出等场景用注释作为列的本地化名称。
+		tag += " comment:" + strconv.Quote(strings.Replace(comment, "`", "'", -1))
+	}
+	return "`" + tag + "`"
+}
+
+func (f *field) singleLineComment() string {
+	return strings.TrimSpace(strings.Replace(f.Column.Comment, "\n", " ", -1))
+}
+
+// Comment returns the trailing comment of a field.
+func (f *field) Comment() string {
+	if f.Column.Comment == "" {
+		return ""
+	}
+	return "// " + f.singleLineComment()
+}
+
+var funcs = template.FuncMap{
+	"quote": func(s string) string { return "\"" + s + "\"" },
+}
+
+var poTemplate = template.Must(template.New("po").Funcs(funcs).Parse(`// Package po generated by 'server generate'
+package po
+{{if .Imports}}
+import (
+{{- range .Imports}}
+	{{quote .}}
+{{- end}}
+)
+{{end}}
+// {{.Name}} .
+type {{.Name}} struct {
+	changes map[string]interface{}
+{{- range .Fields}}
+	{{.Name}} {{.FieldType}} {{.Tag}} {{.Comment}}
+{{- end}}
+}
+
+// TableName .
+func (obj *{{.Name}}) TableName() string {
+	return {{quote .Table.Name}}
+}
+
+// TakeChanges .
+func (obj *{{.Name}}) TakeChanges() map[string]interface{} {
+	if obj.changes == nil {
+		return nil
+	}
+	result := make(map[string]interface{})
+	for k, v := range obj.changes {
+		result[k] = v
+	}
+	obj.changes = nil
+	return result
+}
+
+// updateChanges .
+func (obj *{{.Name}}) setChanges(name string, value interface{}) {
+	if obj.changes == nil {
+		obj.changes = make(map[string]interface{})
+	}
+	obj.changes[name] = value
+}
+{{range .Fields}}{{if not .PrimaryKey}}
+// Set{{.Name}} .
+func (obj *{{$.Name}}) Set{{.Name}}({{.Param}} {{.Type}}) {
+{{- if .Nullable}}
+	obj.{{.Name}} = &{{.Param}}
+{{- else}}
+	obj.{{.Name}} = {{.Param}}
+{{- end}}
+	obj.setChanges({{quote .Column.Name}}, {{.Param}})
+}
+{{if .Nullable}}
+// Set{{.Name}}Null .
+func (obj *{{$.Name}}) Set{{.Name}}Null() {
+	obj.{{.Name}} = nil
+	obj.setChanges({{quote .Column.Name}}, nil)
+}
+{{end}}{{end}}{{end}}
+{{- range .Fields}}{{if .Addable}}
+// Add{{.Name}} .
+func (obj *{{$.Name}}) Add{{.Name}}({{.Param}} {{.Type}}) {
+	obj.{{.Name}} += {{.Param}}
+	obj.setChanges({{quote .Column.Name}}, gorm.Expr("{{.Column.Name}} + ?", {{.Param}}))
+}
+{{end}}{{end}}`))
+
+var repositoryTemplate = template.Must(template.New("repository").Funcs(funcs).Parse(`// Code generated by 'server generate'. DO NOT EDIT.
+
+package repository
+
+import (
+	"fmt"
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/freedom"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+{{range .}}
+// find{{.Name}} .
+func find{{.Name}}(repo GORMRepository, result interface{}, builders ...Builder) (e error) {
+	now := time.Now()
+	defer func() {
+		freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}", e, now)
+		ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}", e, result)
+	}()
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, {{quote .Name}}, "find{{.Name}}")
+		if len(builders) == 0 {
+			return db.Where(result).Last(result).Error
+		}
+		return builders[0].Execute(db.Limit(1), result)
+	})
+	return
+}
+
+// find{{.Name}}ListByPrimarys .
+func find{{.Name}}ListByPrimarys(repo GORMRepository, results interface{}, primarys ...interface{}) (e error) {
+	now := time.Now()
+	e = read(repo, nil, func(db *gorm.DB, _ []Builder) error {
+		return ormDB(repo, db, {{quote .Name}}, "find{{.Name}}ListByPrimarys").Find(results, primarys).Error
+	})
+	freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}ListByPrimarys", e, now)
+	ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}sByPrimarys", e, primarys)
+	return
+}
+
+// find{{.Name}}ByWhere .
+func find{{.Name}}ByWhere(repo GORMRepository, query string, args []interface{}, result interface{}, builders ...Builder) (e error) {
+	now := time.Now()
+	defer func() {
+		freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}ByWhere", e, now)
+		ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}ByWhere", e, query, args)
+	}()
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, {{quote .Name}}, "find{{.Name}}ByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
+
+		return builders[0].Execute(db.Limit(1), result)
+	})
+	return
+}
+
+// find{{.Name}}ByMap .
+func find{{.Name}}ByMap(repo GORMRepository, query map[string]interface{}, result interface{}, builders ...Builder) (e error) {
+	now := time.Now()
+	defer func() {
+		freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}ByMap", e, now)
+		ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}ByMap", e, query)
+	}()
+
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, {{quote .Name}}, "find{{.Name}}ByMap")
+		db = db.Where(query)
+		if len(builders) == 0 {
+			return db.Last(result).Error
+		}
+
+		return builders[0].Execute(db.Limit(1), result)
+	})
+	return
+}
+
+// find{{.Name}}List .
+func find{{.Name}}List(repo GORMRepository, query po.{{.Name}}, results interface{}, builders ...Builder) (e error) {
+	now := time.Now()
+	defer func() {
+		freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}List", e, now)
+		ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}s", e, query)
+	}()
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, {{quote .Name}}, "find{{.Name}}List")
+		db = db.Where(query)
+
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
+	return
+}
+
+// find{{.Name}}ListByWhere .
+func find{{.Name}}ListByWhere(repo GORMRepository, query string, args []interface{}, results interface{}, builders ...Builder) (e error) {
+	now := time.Now()
+	defer func() {
+		freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}ListByWhere", e, now)
+		ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}sByWhere", e, query, args)
+	}()
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, {{quote .Name}}, "find{{.Name}}ListByWhere")
+		if query != "" {
+			db = db.Where(query, args...)
+		}
+
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
+	return
+}
+
+// find{{.Name}}ListByMap .
+func find{{.Name}}ListByMap(repo GORMRepository, query map[string]interface{}, results interface{}, builders ...Builder) (e error) {
+	now := time.Now()
+	defer func() {
+		freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "find{{.Name}}ListByMap", e, now)
+		ormErrorLog(repo, {{quote .Name}}, "find{{.Name}}sByMap", e, query)
+	}()
+
+	e = read(repo, builders, func(db *gorm.DB, builders []Builder) error {
+		db = ormDB(repo, db, {{quote .Name}}, "find{{.Name}}ListByMap")
+		db = db.Where(query)
+
+		if len(builders) == 0 {
+			return db.Find(results).Error
+		}
+		return builders[0].Execute(db, results)
+	})
+	return
+}
+
+// create{{.Name}} .
+func create{{.Name}}(repo GORMRepository, object *po.{{.Name}}) (rowsAffected int64, e error) {
+	now := time.Now()
+	db := ormDB(repo, repo.db(), {{quote .Name}}, "create{{.Name}}").Create(object)
+	rowsAffected = db.RowsAffected
+	e = db.Error
+	freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "create{{.Name}}", e, now)
+	ormErrorLog(repo, {{quote .Name}}, "create{{.Name}}", e, *object)
+	return
+}
+
+// save{{.Name}} .
+func save{{.Name}}(repo GORMRepository, object *po.{{.Name}}) (affected int64, e error) {
+	now := time.Now()
+	db := ormDB(repo, repo.db(), {{quote .Name}}, "save{{.Name}}").Model(object).Updates(object.TakeChanges())
+	e = db.Error
+	affected = db.RowsAffected
+	freedom.Prometheus().OrmWithLabelValues({{quote .Name}}, "save{{.Name}}", e, now)
+	ormErrorLog(repo, {{quote .Name}}, "save{{.Name}}", e, *object)
+	return
+}
+{{end}}
+// create calls the create function of the po type of object.
+func create(repo GORMRepository, object interface{}) (int64, error) {
+	switch object := object.(type) {
+{{- range .}}
+	case *po.{{.Name}}:
+		return create{{.Name}}(repo, object)
+{{- end}}
+	}
+	return 0, fmt.Errorf("no create function of %T", object)
+}
+
+// save calls the save function of the po type of object.
+func save(repo GORMRepository, object interface{}) (int64, error) {
+	switch object := object.(type) {
+{{- range .}}
+	case *po.{{.Name}}:
+		return save{{.Name}}(repo, object)
+{{- end}}
+	}
+	return 0, fmt.Errorf("no save function of %T", object)
+}
+`))
+
+// RenderPO renders the po file of a table.
+func RenderPO(table *Table) ([]byte, error) {
+	return render(poTemplate, newEntity(table))
+}
+
+// RenderRepository renders the repository helpers of every table.
+func RenderRepository(tables []*Table) ([]byte, error) {
+	entities := []*entity{}
+	for _, table := range tables {
+		entities = append(entities, newEntity(table))
+	}
+	return render(repositoryTemplate, entities)
+}
+
+func render(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
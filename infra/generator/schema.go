@@ -0,0 +1,383 @@
+package generator
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/8treenet/dump/infra/migration"
+)
+
+// Table .
+type Table struct {
+	Name    string
+	Columns []*Column
+}
+
+// Column .
+type Column struct {
+	Name       string
+	Type       string // database type, e.g. int(11), varchar(255), datetime
+	Nullable   bool
+	PrimaryKey bool
+	Comment    string
+}
+
+// ignoredTables are not mapped to po types.
+var ignoredTables = map[string]bool{"schema_migrations": true}
+
+// FromDB introspects the live schema of a mysql, postgres or sqlite3 database.
+func FromDB(db *sql.DB, driver string) ([]*Table, error) {
+	switch driver {
+	case "mysql":
+		return fromMySQL(db)
+	case "postgres":
+		return fromPostgres(db)
+	case "sqlite3":
+		return fromSQLite(db)
+	}
+	return nil, fmt.Errorf("unsupported driver '%s'", driver)
+}
+
+func fromMySQL(db *sql.DB) ([]*Table, error) {
+	rows, err := db.Query(`SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_COMMENT
+		FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME, ORDINAL_POSITION`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := newTableSet()
+	for rows.Next() {
+		var tableName, nullable, key string
+		column := &Column{}
+		if err := rows.Scan(&tableName, &column.Name, &column.Type, &nullable, &key, &column.Comment); err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == "YES"
+		column.PrimaryKey = key == "PRI"
+		tables.add(tableName, column)
+	}
+	return tables.list(), rows.Err()
+}
+
+func fromPostgres(db *sql.DB) ([]*Table, error) {
+	primaryKeys := map[string]bool{}
+	keyRows, err := db.Query(`SELECT kcu.table_name, kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+		WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = current_schema()`)
+	if err != nil {
+		return nil, err
+	}
+	defer keyRows.Close()
+	for keyRows.Next() {
+		var tableName, columnName string
+		if err := keyRows.Scan(&tableName, &columnName); err != nil {
+			return nil, err
+		}
+		primaryKeys[tableName+"."+columnName] = true
+	}
+
+	rows, err := db.Query(`SELECT c.table_name, c.column_name, c.data_type, c.is_nullable,
+		COALESCE(col_description((quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass, c.ordinal_position), '')
+		FROM information_schema.columns c WHERE c.table_schema = current_schema() ORDER BY c.table_name, c.ordinal_position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := newTableSet()
+	for rows.Next() {
+		var tableName, nullable string
+		column := &Column{}
+		if err := rows.Scan(&tableName, &column.Name, &column.Type, &nullable, &column.Comment); err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == "YES"
+		column.PrimaryKey = primaryKeys[tableName+"."+column.Name]
+		tables.add(tableName, column)
+	}
+	return tables.list(), rows.Err()
+}
+
+func fromSQLite(db *sql.DB) ([]*Table, error) {
+	names := []string{}
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	tables := newTableSet()
+	for _, name := range names {
+		columnRows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%q)", name))
+		if err != nil {
+			return nil, err
+		}
+		for columnRows.Next() {
+			var cid, notNull, pk int
+			var defaultValue sql.NullString
+			column := &Column{}
+			if err := columnRows.Scan(&cid, &column.Name, &column.Type, &notNull, &defaultValue, &pk); err != nil {
+				columnRows.Close()
+				return nil, err
+			}
+			column.PrimaryKey = pk > 0
+			column.Nullable = notNull == 0 && !column.PrimaryKey
+			tables.add(name, column)
+		}
+		columnRows.Close()
+	}
+	return tables.list(), nil
+}
+
+var (
+	createTable = regexp.MustCompile("(?is)^CREATE\\s+TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?[`\"]?(\\w+)[`\"]?\\s*\\((.*)\\)[^)]*$")
+	dropTable   = regexp.MustCompile("(?is)^DROP\\s+TABLE\\s+(?:IF\\s+EXISTS\\s+)?[`\"]?(\\w+)[`\"]?")
+	addColumn   = regexp.MustCompile("(?is)^ALTER\\s+TABLE\\s+[`\"]?(\\w+)[`\"]?\\s+ADD\\s+(?:COLUMN\\s+)?(.*)$")
+	dropColumn  = regexp.MustCompile("(?is)^ALTER\\s+TABLE\\s+[`\"]?(\\w+)[`\"]?\\s+DROP\\s+(?:COLUMN\\s+)?[`\"]?(\\w+)[`\"]?$")
+	primaryKey  = regexp.MustCompile("(?is)^PRIMARY\\s+KEY\\s*\\((.*)\\)")
+	comment     = regexp.MustCompile(`(?is)\bCOMMENT\s+'((?:[^'\\]|\\.|'')*)'`)
+	commentOn   = regexp.MustCompile("(?is)^COMMENT\\s+ON\\s+COLUMN\\s+[`\"]?(\\w+)[`\"]?\\.[`\"]?(\\w+)[`\"]?\\s+IS\\s+'((?:[^']|'')*)'$")
+)
+
+// FromMigrations replays the CREATE TABLE, DROP TABLE, ALTER TABLE ADD/DROP COLUMN and COMMENT ON COLUMN
+// statements of the up SQL migrations of dir for dialect in version order, a file of the dialect,
+// e.g. 1_baseline.mysql.up.sql, replaces the common file of its version. Go migrations are not read.
+func FromMigrations(dir, dialect string) ([]*Table, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[string]string{}
+	for _, entry := range entries {
+		match := migration.FileName.FindStringSubmatch(entry.Name())
+		if match == nil || match[4] != "up" || (match[3] != "" && match[3] != dialect) {
+			continue
+		}
+		version := versionOf(entry.Name())
+		if _, ok := byVersion[version]; ok && match[3] == "" {
+			continue
+		}
+		byVersion[version] = filepath.Join(dir, entry.Name())
+	}
+	files := []string{}
+	for _, file := range byVersion {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return versionOf(files[i]) < versionOf(files[j])
+	})
+
+	tables := newTableSet()
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, statement := range splitStatements(string(content)) {
+			if match := createTable.FindStringSubmatch(statement); match != nil {
+				delete(tables.byName, match[1])
+				table := tables.table(match[1])
+				for _, item := range splitTopLevel(match[2]) {
+					if key := primaryKey.FindStringSubmatch(item); key != nil {
+						for _, name := range strings.Split(key[1], ",") {
+							if column := table.column(unquote(name)); column != nil {
+								column.PrimaryKey = true
+								column.Nullable = false
+							}
+						}
+						continue
+					}
+					if column := parseColumn(item); column != nil {
+						table.Columns = append(table.Columns, column)
+					}
+				}
+				continue
+			}
+			if match := dropTable.FindStringSubmatch(statement); match != nil {
+				delete(tables.byName, match[1])
+				continue
+			}
+			if match := dropColumn.FindStringSubmatch(statement); match != nil {
+				table := tables.table(match[1])
+				for index, column := range table.Columns {
+					if column.Name == match[2] {
+						table.Columns = append(table.Columns[:index], table.Columns[index+1:]...)
+						break
+					}
+				}
+				continue
+			}
+			if match := commentOn.FindStringSubmatch(statement); match != nil {
+				if column := tables.table(match[1]).column(match[2]); column != nil {
+					column.Comment = strings.Replace(match[3], "''", "'", -1)
+				}
+				continue
+			}
+			if match := addColumn.FindStringSubmatch(statement); match != nil {
+				if column := parseColumn(match[2]); column != nil {
+					table := tables.table(match[1])
+					table.Columns = append(table.Columns, column)
+				}
+			}
+		}
+	}
+	return tables.list(), nil
+}
+
+// parseColumn parses a column definition, nil for keys and constraints.
+func parseColumn(definition string) *Column {
+	fields := strings.Fields(definition)
+	if len(fields) < 2 {
+		return nil
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "PRIMARY", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN", "FULLTEXT", "CHECK":
+		return nil
+	}
+
+	upper := strings.ToUpper(definition)
+	column := &Column{Name: unquote(fields[0]), Type: strings.ToLower(fields[1])}
+	column.PrimaryKey = strings.Contains(upper, "PRIMARY KEY")
+	column.Nullable = !strings.Contains(upper, "NOT NULL") && !column.PrimaryKey
+	if match := comment.FindStringSubmatch(definition); match != nil {
+		column.Comment = strings.Replace(match[1], "''", "'", -1)
+	}
+	return column
+}
+
+// splitTopLevel splits the body of CREATE TABLE on the commas outside parentheses and quotes.
+func splitTopLevel(body string) []string {
+	result := []string{}
+	depth := 0
+	var quote rune
+	start := 0
+	for index, char := range body {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == ',' && depth == 0:
+			result = append(result, strings.TrimSpace(body[start:index]))
+			start = index + 1
+		}
+	}
+	return append(result, strings.TrimSpace(body[start:]))
+}
+
+func splitStatements(content string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	result := []string{}
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";\n") {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if statement != "" {
+			result = append(result, statement)
+		}
+	}
+	return result
+}
+
+func versionOf(file string) string {
+	name := filepath.Base(file)
+	if pos := strings.Index(name, "_"); pos > 0 {
+		return fmt.Sprintf("%020s", name[:pos])
+	}
+	return name
+}
+
+func unquote(name string) string {
+	return strings.Trim(strings.TrimSpace(name), "`\"")
+}
+
+type tableSet struct {
+	byName map[string]*Table
+}
+
+func newTableSet() *tableSet {
+	return &tableSet{byName: map[string]*Table{}}
+}
+
+func (set *tableSet) table(name string) *Table {
+	table, ok := set.byName[name]
+	if !ok {
+		table = &Table{Name: name}
+		set.byName[name] = table
+	}
+	return table
+}
+
+func (set *tableSet) add(tableName string, column *Column) {
+	set.table(tableName).Columns = append(set.table(tableName).Columns, column)
+}
+
+// list returns the tables ordered by name.
+func (set *tableSet) list() []*Table {
+	result := []*Table{}
+	for name, table := range set.byName {
+		if ignoredTables[name] || len(table.Columns) == 0 {
+			continue
+		}
+		result = append(result, table)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (table *Table) column(name string) *Column {
+	for _, column := range table.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// Select returns the tables of names, e.g. the tables of the project in a database shared with other applications.
+func Select(tables []*Table, names []string) ([]*Table, error) {
+	byName := map[string]*Table{}
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+	result := []*Table{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		table, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("table '%s' not found", name)
+		}
+		result = append(result, table)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
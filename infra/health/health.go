@@ -0,0 +1,120 @@
+// Package health provides the /healthz and /readyz probes and a registry of dependency checks.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/8treenet/freedom"
+)
+
+// Timeout bounds every check of a readiness probe.
+var Timeout = 2 * time.Second
+
+// Check reports whether a dependency is usable.
+type Check func(ctx context.Context) error
+
+// Result .
+type Result struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Report .
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+var (
+	checks       = map[string]Check{}
+	checksMu     sync.RWMutex
+	shuttingDown int32
+)
+
+// Register adds a readiness check, any module can register its own dependencies.
+func Register(name string, check Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks[name] = check
+}
+
+// SetShuttingDown makes the readiness probe fail, it is called at the start of the graceful shutdown.
+func SetShuttingDown() {
+	atomic.StoreInt32(&shuttingDown, 1)
+}
+
+// Drain makes the readiness probe fail and waits period, so that the load balancers see the failing probe
+// and stop routing new requests before the listeners close. It must run before the shutdown of the server,
+// e.g. iris.RegisterOnInterrupt(func() { health.Drain(5 * time.Second) }) before the application runs.
+func Drain(period time.Duration) {
+	SetShuttingDown()
+	time.Sleep(period)
+}
+
+// ShuttingDown .
+func ShuttingDown() bool {
+	return atomic.LoadInt32(&shuttingDown) == 1
+}
+
+// Ready runs every check concurrently.
+func Ready(ctx context.Context) Report {
+	checksMu.RLock()
+	list := map[string]Check{}
+	for name, check := range checks {
+		list[name] = check
+	}
+	checksMu.RUnlock()
+
+	report := Report{Status: "ok", Checks: map[string]Result{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range list {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, Timeout)
+			defer cancel()
+			now := time.Now()
+			err := check(checkCtx)
+			result := Result{Status: "ok", Latency: time.Since(now).String()}
+			if err != nil {
+				result.Status = "fail"
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			report.Checks[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != "ok" {
+			report.Status = "fail"
+		}
+	}
+	if ShuttingDown() {
+		report.Status = "fail"
+		report.Checks["shutdown"] = Result{Status: "fail", Latency: "0s", Error: "shutting down"}
+	}
+	return report
+}
+
+// Liveness handles /healthz, it only reports that the process is up.
+func Liveness(ctx freedom.Context) {
+	ctx.JSON(Report{Status: "ok", Checks: map[string]Result{}})
+}
+
+// Readiness handles /readyz, it responds 503 when any check fails.
+func Readiness(ctx freedom.Context) {
+	report := Ready(ctx.Request().Context())
+	if report.Status != "ok" {
+		ctx.StatusCode(http.StatusServiceUnavailable)
+	}
+	ctx.JSON(report)
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/core/host"
+)
+
+// TestDrain checks that readiness fails for the whole drain period before the server shutdown registered after it runs,
+// as the interrupt callbacks run in registration order.
+func TestDrain(t *testing.T) {
+	defer atomic.StoreInt32(&shuttingDown, 0)
+	period := 100 * time.Millisecond
+	var drained time.Time
+	host.RegisterOnInterrupt(func() {
+		if Ready(context.Background()).Status != "ok" {
+			t.Error("expected ready before the interrupt")
+		}
+		drained = time.Now()
+		Drain(period)
+	})
+
+	ready := "ok"
+	var elapsed time.Duration
+	//模拟 app.Run 注册的优雅关闭
+	host.RegisterOnInterrupt(func() {
+		ready = Ready(context.Background()).Status
+		elapsed = time.Since(drained)
+	})
+	host.Interrupt.FireNow()
+
+	if ready != "fail" {
+		t.Fatalf("readiness %s when the server shut down, expected fail", ready)
+	}
+	if elapsed < period {
+		t.Fatalf("the server shut down %s after readiness failed, expected at least %s", elapsed, period)
+	}
+}
@@ -0,0 +1,204 @@
+// Package importer reads CSV or JSON Lines files row by row into structs.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Formats of an import.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// Reader decodes the rows of a file into new structs, the CSV header and JSON keys are the json tags.
+type Reader struct {
+	next   func() (map[string]interface{}, error)
+	newRow func() interface{}
+	line   int
+}
+
+// NewReader . newRow returns a pointer to a new row struct, e.g. func() interface{} { return &dto.GoodsImport{} }.
+func NewReader(format string, r io.Reader, newRow func() interface{}) (*Reader, error) {
+	result := &Reader{newRow: newRow}
+	switch format {
+	case FormatCSV:
+		result.next = csvRows(r)
+	case FormatJSONL:
+		result.next = jsonlRows(r)
+	default:
+		return nil, fmt.Errorf("unknown format '%s', expected csv or jsonl", format)
+	}
+	return result, nil
+}
+
+// RowError is an invalid row, the reader can go on with the next one.
+type RowError struct {
+	Err error
+}
+
+// Error .
+func (e *RowError) Error() string {
+	return e.Err.Error()
+}
+
+// Next returns the next row and its number, starting at 1. io.EOF ends the file,
+// a *RowError concerns this row only, any other error means the file can't be read further.
+func (r *Reader) Next() (row interface{}, number int, err error) {
+	values, err := r.next()
+	if err == io.EOF {
+		return nil, r.line, err
+	}
+	if _, ok := err.(*RowError); !ok && err != nil {
+		return nil, r.line, err
+	}
+	r.line++
+	if err != nil {
+		return nil, r.line, err
+	}
+	row = r.newRow()
+	if err := decode(values, row); err != nil {
+		return nil, r.line, &RowError{Err: err}
+	}
+	return row, r.line, nil
+}
+
+func csvRows(r io.Reader) func() (map[string]interface{}, error) {
+	reader := csv.NewReader(skipBOM(r))
+	reader.FieldsPerRecord = -1
+	var header []string
+	return func() (map[string]interface{}, error) {
+		if header == nil {
+			var err error
+			if header, err = reader.Read(); err != nil {
+				if err == io.EOF {
+					return nil, err
+				}
+				return nil, fmt.Errorf("header: %v", err)
+			}
+			for index := range header {
+				header[index] = strings.TrimSpace(header[index])
+			}
+		}
+		record, err := reader.Read()
+		if _, ok := err.(*csv.ParseError); ok {
+			return nil, &RowError{Err: err}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) != len(header) {
+			return nil, &RowError{Err: fmt.Errorf("expected %d fields, got %d", len(header), len(record))}
+		}
+		result := map[string]interface{}{}
+		for index, key := range header {
+			result[key] = record[index]
+		}
+		return result, nil
+	}
+}
+
+func jsonlRows(r io.Reader) func() (map[string]interface{}, error) {
+	reader := bufio.NewReader(skipBOM(r))
+	return func() (map[string]interface{}, error) {
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) == 0 {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			result := map[string]interface{}{}
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			if err := decoder.Decode(&result); err != nil {
+				return nil, &RowError{Err: err}
+			}
+			return result, nil
+		}
+	}
+}
+
+// skipBOM drops the UTF-8 byte order mark spreadsheet programs put at the start of a file.
+func skipBOM(r io.Reader) io.Reader {
+	reader := bufio.NewReader(r)
+	if bom, err := reader.Peek(3); err == nil && string(bom) == "\xEF\xBB\xBF" {
+		reader.Discard(3)
+	}
+	return reader
+}
+
+// decode sets the fields of row from values keyed by json name, unknown keys are an error.
+func decode(values map[string]interface{}, row interface{}) error {
+	value := reflect.ValueOf(row).Elem()
+	fields := map[string]reflect.Value{}
+	for index := 0; index < value.NumField(); index++ {
+		name := strings.Split(value.Type().Field(index).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = value.Field(index)
+		}
+	}
+	for key, item := range values {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown column '%s'", key)
+		}
+		if item == nil {
+			continue
+		}
+		if err := set(field, fmt.Sprint(item)); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func set(field reflect.Value, text string) error {
+	text = strings.TrimSpace(text)
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if text == "" {
+			return nil
+		}
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' is not an integer", text)
+		}
+		field.SetInt(value)
+	case reflect.Float32, reflect.Float64:
+		if text == "" {
+			return nil
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a number", text)
+		}
+		field.SetFloat(value)
+	case reflect.Bool:
+		if text == "" {
+			return nil
+		}
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a boolean", text)
+		}
+		field.SetBool(value)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
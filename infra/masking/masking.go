@@ -0,0 +1,197 @@
+// Package masking applies sensitive-column policies to po values before they leave the process,
+// in exports, API responses and logs.
+//
+// A po type declares its policy with a MaskingPolicy method returning column to action,
+// masking.toml overrides it per environment and provides the salt of the deterministic actions.
+package masking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Actions of a column policy.
+const (
+	// Keep leaves the column as it is, it only makes sense as an override.
+	Keep = "keep"
+	// Mask keeps the first character of a string, other types become their zero value.
+	Mask = "mask"
+	// Hash replaces the value by a salted digest, equal values give equal digests.
+	Hash = "hash"
+	// Drop removes the column, a dump writes its zero value instead to keep NOT NULL columns restorable.
+	Drop = "drop"
+	// Fake replaces the value by a deterministic fake of the same type, e.g. name_3fa2c1d0.
+	Fake = "fake"
+)
+
+// Actions lists the valid actions.
+var Actions = []string{Keep, Mask, Hash, Drop, Fake}
+
+// Policier is implemented by po types with sensitive columns.
+type Policier interface {
+	MaskingPolicy() map[string]string
+}
+
+var (
+	mu        sync.RWMutex
+	salt      []byte
+	overrides = map[string]string{}
+	policies  = map[reflect.Type]map[string]string{}
+	needs     = map[reflect.Type]bool{}
+	//可能持有接口值的类型, 与策略无关, Configure 不重置
+	interfaces = map[reflect.Type]bool{}
+)
+
+// Configure sets the salt of Hash and Fake and the "table.column" = action overrides.
+func Configure(newSalt string, newOverrides map[string]string) error {
+	for key, action := range newOverrides {
+		if !strings.Contains(key, ".") {
+			return fmt.Errorf("override '%s': expected table.column", key)
+		}
+		if !valid(action) {
+			return fmt.Errorf("override '%s': unknown action '%s'", key, action)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	salt = []byte(newSalt)
+	overrides = map[string]string{}
+	for key, action := range newOverrides {
+		overrides[key] = action
+	}
+	policies = map[reflect.Type]map[string]string{}
+	needs = map[reflect.Type]bool{}
+	return nil
+}
+
+func valid(action string) bool {
+	for _, item := range Actions {
+		if item == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy returns the effective column policy of a po object, nil when no column is masked.
+func Policy(object interface{}) map[string]string {
+	t := reflect.TypeOf(object)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return policyOf(t)
+}
+
+func policyOf(t reflect.Type) map[string]string {
+	mu.RLock()
+	policy, ok := policies[t]
+	mu.RUnlock()
+	if ok {
+		return policy
+	}
+
+	result := map[string]string{}
+	object := reflect.New(t).Interface()
+	if policier, ok := object.(Policier); ok {
+		for column, action := range policier.MaskingPolicy() {
+			result[column] = action
+		}
+	}
+	if tabler, ok := object.(interface{ TableName() string }); ok {
+		prefix := tabler.TableName() + "."
+		mu.RLock()
+		for key, action := range overrides {
+			if strings.HasPrefix(key, prefix) {
+				result[strings.TrimPrefix(key, prefix)] = action
+			}
+		}
+		mu.RUnlock()
+	}
+	for column, action := range result {
+		if action == Keep {
+			delete(result, column)
+		}
+	}
+	if len(result) == 0 {
+		result = nil
+	}
+	mu.Lock()
+	policies[t] = result
+	mu.Unlock()
+	return result
+}
+
+// Apply applies action to the value of column, keep is false when the column is dropped.
+func Apply(action, column string, value interface{}) (result interface{}, keep bool) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, action != Drop
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, action != Drop
+	}
+
+	switch action {
+	case Drop:
+		return nil, false
+	case Mask:
+		if rv.Kind() == reflect.String {
+			runes := []rune(rv.String())
+			if len(runes) <= 1 {
+				return "***", true
+			}
+			return string(runes[0]) + "***", true
+		}
+		return reflect.Zero(rv.Type()).Interface(), true
+	case Hash:
+		if rv.Kind() == reflect.String && rv.String() == "" {
+			return "", true
+		}
+		return digest(rv.Interface()), true
+	case Fake:
+		return fake(column, rv), true
+	}
+	return rv.Interface(), true
+}
+
+// digest is the hex HMAC-SHA256 of value keyed by the salt.
+func digest(value interface{}) string {
+	mu.RLock()
+	mac := hmac.New(sha256.New, salt)
+	mu.RUnlock()
+	mac.Write([]byte(fmt.Sprint(value)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// fake derives a value of the same kind from the digest, numbers stay below one million.
+func fake(column string, value reflect.Value) interface{} {
+	sum := digest(value.Interface())
+	number, _ := strconv.ParseUint(sum[:8], 16, 64)
+	number %= 1000000
+	switch value.Kind() {
+	case reflect.String:
+		if value.String() == "" {
+			return ""
+		}
+		return column + "_" + sum[:8]
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.ValueOf(int64(number)).Convert(value.Type()).Interface()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(number).Convert(value.Type()).Interface()
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(float64(number)).Convert(value.Type()).Interface()
+	}
+	return reflect.Zero(value.Type()).Interface()
+}
@@ -0,0 +1,256 @@
+package masking
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Value returns v with the policies applied. Every po value with a policy, directly or nested in
+// pointers, slices, maps, structs and interfaces, is replaced by a map keyed by its json names, and so
+// are the structs holding one. Values without a po value with a policy inside are returned as they are.
+func Value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return walk(reflect.ValueOf(v))
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// needsMasking reports whether every value of t may contain a po value with a policy, interface values are not followed.
+func needsMasking(t reflect.Type) bool {
+	mu.RLock()
+	result, ok := needs[t]
+	mu.RUnlock()
+	if ok {
+		return result
+	}
+	result = computeNeeds(t, map[reflect.Type]bool{})
+	mu.Lock()
+	needs[t] = result
+	mu.Unlock()
+	return result
+}
+
+func computeNeeds(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	if t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType) {
+		//自定义序列化的类型保持原样。
+		return t.Kind() == reflect.Struct && policyOf(t) != nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return computeNeeds(t.Elem(), visiting)
+	case reflect.Struct:
+		if policyOf(t) != nil {
+			return true
+		}
+		for index := 0; index < t.NumField(); index++ {
+			field := t.Field(index)
+			if field.PkgPath == "" && computeNeeds(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// holdsInterface reports whether a value of t may hold an interface value, whose dynamic type
+// decides whether it needs masking.
+func holdsInterface(t reflect.Type) bool {
+	mu.RLock()
+	result, ok := interfaces[t]
+	mu.RUnlock()
+	if ok {
+		return result
+	}
+	result = computeHolds(t, map[reflect.Type]bool{})
+	mu.Lock()
+	interfaces[t] = result
+	mu.Unlock()
+	return result
+}
+
+func computeHolds(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] || t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType) {
+		return false
+	}
+	visiting[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return computeHolds(t.Elem(), visiting)
+	case reflect.Struct:
+		for index := 0; index < t.NumField(); index++ {
+			field := t.Field(index)
+			if field.PkgPath == "" && computeHolds(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contains reports whether v contains a po value with a policy, following the interface values.
+func contains(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if needsMasking(v.Type()) {
+		return true
+	}
+	if !holdsInterface(v.Type()) {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && contains(v.Elem())
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < v.Len(); index++ {
+			if contains(v.Index(index)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if contains(iter.Value()) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		for index := 0; index < v.NumField(); index++ {
+			if v.Type().Field(index).PkgPath == "" && contains(v.Field(index)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func walk(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if !contains(v) {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walk(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		result := make([]interface{}, v.Len())
+		for index := range result {
+			result[index] = walk(v.Index(index))
+		}
+		return result
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		result := map[string]interface{}{}
+		iter := v.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = walk(iter.Value())
+		}
+		return result
+	case reflect.Struct:
+		return walkStruct(v)
+	}
+	return v.Interface()
+}
+
+// walkStruct converts a struct to a map keyed by its json names, applying the policy of po types.
+func walkStruct(v reflect.Value) map[string]interface{} {
+	t := v.Type()
+	policy := policyOf(t)
+	result := map[string]interface{}{}
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, omitempty := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		value := v.Field(index)
+		if field.Anonymous && name == field.Name && reflect.Indirect(value).Kind() == reflect.Struct {
+			//匿名嵌入的结构体字段提升到外层, 与 encoding/json 一致。
+			if value.Kind() == reflect.Ptr && value.IsNil() {
+				continue
+			}
+			for key, item := range walkStruct(reflect.Indirect(value)) {
+				if _, ok := result[key]; !ok {
+					result[key] = item
+				}
+			}
+			continue
+		}
+		if action, ok := policy[columnName(field)]; ok {
+			masked, keep := Apply(action, columnName(field), value.Interface())
+			if keep {
+				result[name] = masked
+			}
+			continue
+		}
+		if omitempty && isEmpty(value) {
+			continue
+		}
+		result[name] = walk(value)
+	}
+	return result
+}
+
+// jsonName returns the json name of field, "-" when it is skipped.
+func jsonName(field reflect.StructField) (name string, omitempty bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "-", false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return
+}
+
+// columnName returns the gorm column of field, the lower case field name without a column tag.
+func columnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(setting, "column:") {
+			return strings.TrimPrefix(setting, "column:")
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
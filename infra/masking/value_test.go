@@ -0,0 +1,56 @@
+package masking
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type maskedUser struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (obj *maskedUser) MaskingPolicy() map[string]string {
+	return map[string]string{"password": Drop, "name": Mask}
+}
+
+type envelope struct {
+	Total   int         `json:"total"`
+	Payload interface{} `json:"payload"`
+	Created time.Time   `json:"created"`
+}
+
+// TestValue checks that only the po values with a policy and the values holding them are rewritten.
+func TestValue(t *testing.T) {
+	user := maskedUser{ID: 1, Name: "alice", Password: "secret"}
+	created := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	plain := envelope{Total: 1, Payload: []int{1, 2}, Created: created}
+
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{user, `{"id":1,"name":"a***"}`},
+		{[]*maskedUser{&user}, `[{"id":1,"name":"a***"}]`},
+		{map[string]interface{}{"user": user, "total": 1}, `{"total":1,"user":{"id":1,"name":"a***"}}`},
+		{[]interface{}{user, plain}, `[{"id":1,"name":"a***"},{"total":1,"payload":[1,2],"created":"2020-05-01T00:00:00Z"}]`},
+		{plain, `{"total":1,"payload":[1,2],"created":"2020-05-01T00:00:00Z"}`},
+		{envelope{Total: 1, Payload: &user, Created: created}, `{"created":"2020-05-01T00:00:00Z","payload":{"id":1,"name":"a***"},"total":1}`},
+	}
+	for _, item := range cases {
+		content, err := json.Marshal(Value(item.value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != item.expected {
+			t.Errorf("%T: %s, expected %s", item.value, content, item.expected)
+		}
+	}
+
+	//没有策略的结构体原样返回, 不转换为map
+	if _, ok := Value(plain).(envelope); !ok {
+		t.Errorf("a struct without a policy was rewritten to %T", Value(plain))
+	}
+}
@@ -0,0 +1,5 @@
+package metrics
+
+// Requests is the request counter, read by the tests of package metrics_test,
+// which can't be in package metrics as the apptest harness imports it.
+var Requests = requests
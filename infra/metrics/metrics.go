@@ -0,0 +1,164 @@
+// Package metrics records the Prometheus metrics of the requests served by the controllers,
+// exposed with the other metrics of freedom on prometheus_listen_addr.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	iriscontext "github.com/kataras/iris/v12/context"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Labels of unknown values, they bound the number of series.
+const (
+	UnmatchedRoute = "unmatched" // 没有匹配的路由, 例如404
+	OtherValue     = "other"
+)
+
+// MaxRoutes and MaxCodes bound the distinct routes and business codes, the next ones are labelled OtherValue.
+var (
+	MaxRoutes = 500
+	MaxCodes  = 100
+)
+
+var (
+	// LatencyBuckets are the latency buckets in seconds, fine enough under 1s for latency objectives.
+	LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	// SizeBuckets are the size buckets in bytes, from 100B to 100MB.
+	SizeBuckets = prometheus.ExponentialBuckets(100, 10, 7)
+)
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_server_requests_total",
+		Help: "Requests served, by route template, method, status and business code.",
+	}, []string{"route", "method", "status", "code"})
+	latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_duration_seconds",
+		Help:    "Latency of the requests, by route template, method, status and business code.",
+		Buckets: LatencyBuckets,
+	}, []string{"route", "method", "status", "code"})
+	inFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_server_requests_in_flight",
+		Help: "Requests being served, by route template and method.",
+	}, []string{"route", "method"})
+	requestSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_size_bytes",
+		Help:    "Size of the request bodies, by route template and method.",
+		Buckets: SizeBuckets,
+	}, []string{"route", "method"})
+	responseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_response_size_bytes",
+		Help:    "Size of the response bodies, by route template, method and status.",
+		Buckets: SizeBuckets,
+	}, []string{"route", "method", "status"})
+)
+
+func init() {
+	prometheus.MustRegister(requests, latency, inFlight, requestSize, responseSize)
+}
+
+// NewMiddleware records the metrics of every request, labelled by its route template, e.g. /user/{username}.
+// The business code is the one JSONResponse sets in ctx.Values()["code"], "" when the handler sets none.
+func NewMiddleware() iriscontext.Handler {
+	return func(ctx iriscontext.Context) {
+		start := time.Now()
+		route := routes.label(routeOf(ctx))
+		method := methodOf(ctx.Method())
+		inFlight.WithLabelValues(route, method).Inc()
+		if size := ctx.Request().ContentLength; size >= 0 {
+			requestSize.WithLabelValues(route, method).Observe(float64(size))
+		}
+
+		defer func() {
+			inFlight.WithLabelValues(route, method).Dec()
+			status := ctx.GetStatusCode()
+			//panic由Recover中间件处理, 这里按500记录后继续抛出
+			r := recover()
+			if r != nil {
+				status = http.StatusInternalServerError
+			}
+			statusLabel := strconv.Itoa(status)
+			code := codes.label(codeOf(ctx))
+			requests.WithLabelValues(route, method, statusLabel, code).Inc()
+			latency.WithLabelValues(route, method, statusLabel, code).Observe(time.Since(start).Seconds())
+			written := ctx.ResponseWriter().Written()
+			if written < 0 {
+				written = 0 //未写入时为-1
+			}
+			responseSize.WithLabelValues(route, method, statusLabel).Observe(float64(written))
+			if r != nil {
+				panic(r)
+			}
+		}()
+		ctx.Next()
+	}
+}
+
+func routeOf(ctx iriscontext.Context) string {
+	route := ctx.GetCurrentRoute()
+	if route == nil || route.Path() == "" {
+		return UnmatchedRoute
+	}
+	return route.Path()
+}
+
+var methods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// methodOf bounds the method label, a client can send any method.
+func methodOf(method string) string {
+	if methods[method] {
+		return method
+	}
+	return OtherValue
+}
+
+// codeOf returns the business code of the response, the non-numeric ones are labelled OtherValue.
+func codeOf(ctx iriscontext.Context) string {
+	code := ctx.Values().GetString("code")
+	if code == "" {
+		return ""
+	}
+	if _, err := strconv.Atoi(code); err != nil {
+		return OtherValue
+	}
+	return code
+}
+
+// labelSet keeps the first values of a label up to a maximum.
+type labelSet struct {
+	mu     sync.RWMutex
+	values map[string]bool
+	max    *int
+}
+
+var (
+	routes = &labelSet{values: map[string]bool{UnmatchedRoute: true}, max: &MaxRoutes}
+	codes  = &labelSet{values: map[string]bool{"": true}, max: &MaxCodes}
+)
+
+// label returns value while the set has room for it, OtherValue otherwise.
+func (s *labelSet) label(value string) string {
+	s.mu.RLock()
+	known := s.values[value]
+	s.mu.RUnlock()
+	if known {
+		return value
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values[value] {
+		return value
+	}
+	if len(s.values) >= *s.max {
+		return OtherValue
+	}
+	s.values[value] = true
+	return value
+}
@@ -0,0 +1,169 @@
+package metrics_test
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/8treenet/dump/infra"
+	"github.com/8treenet/dump/infra/apptest"
+	"github.com/8treenet/dump/infra/metrics"
+	"github.com/8treenet/freedom"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var harness *apptest.Harness
+
+func init() {
+	freedom.Prepare(func(initiator freedom.Initiator) {
+		initiator.BindController("/metricstest", &MetricsController{})
+	})
+}
+
+// MetricsController records the route templates the middleware should label the requests with.
+type MetricsController struct {
+	Worker freedom.Worker
+}
+
+var (
+	routesMu sync.Mutex
+	served   = map[string]string{}
+)
+
+func (c *MetricsController) serve(name string) {
+	routesMu.Lock()
+	served[name] = c.Worker.IrisContext().GetCurrentRoute().Path()
+	routesMu.Unlock()
+}
+
+func route(name string) string {
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	return served[name]
+}
+
+// GetBy handles the GET: /metricstest/{id:int} route.
+func (c *MetricsController) GetBy(id int) string {
+	c.serve("item")
+	return "ok"
+}
+
+// GetCodeBy handles the GET: /metricstest/code/{code:int} route.
+func (c *MetricsController) GetCodeBy(code int) infra.JSONResponse {
+	c.serve("code")
+	return infra.JSONResponse{Code: code}
+}
+
+// GetLate handles the GET: /metricstest/late route, requested after the routes are capped.
+func (c *MetricsController) GetLate() string {
+	c.serve("late")
+	return "ok"
+}
+
+// GetPanic handles the GET: /metricstest/panic route.
+func (c *MetricsController) GetPanic() string {
+	c.serve("panic")
+	panic("metrics test")
+}
+
+func TestMain(m *testing.M) {
+	var err error
+	if harness, err = apptest.New(apptest.Options{}); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	harness.Close()
+	os.Exit(code)
+}
+
+// count returns the requests of the labels.
+func count(route, method, status, code string) float64 {
+	return testutil.ToFloat64(metrics.Requests.WithLabelValues(route, method, status, code))
+}
+
+func request(t *testing.T, method, path string, status int) {
+	t.Helper()
+	resp, err := harness.Client.Do(method, path, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s: status %d, expected %d", method, path, resp.StatusCode, status)
+	}
+}
+
+// TestRouteTemplate checks that the requests of a route share its template label, not their paths.
+func TestRouteTemplate(t *testing.T) {
+	request(t, "GET", "/metricstest/1", http.StatusOK)
+	template := route("item")
+	before := count(template, "GET", "200", "")
+	request(t, "GET", "/metricstest/2", http.StatusOK)
+	request(t, "GET", "/metricstest/3", http.StatusOK)
+	if got := count(template, "GET", "200", "") - before; got != 2 {
+		t.Fatalf("%v requests labelled %s, expected 2", got, template)
+	}
+	if got := count("/metricstest/2", "GET", "200", ""); got != 0 {
+		t.Fatalf("%v requests labelled with the path", got)
+	}
+}
+
+// TestUnmatched checks the label of the requests without a route and the bound of the methods.
+func TestUnmatched(t *testing.T) {
+	before := count(metrics.UnmatchedRoute, "GET", "404", "")
+	request(t, "GET", "/metricstest/missing/path", http.StatusNotFound)
+	if got := count(metrics.UnmatchedRoute, "GET", "404", "") - before; got != 1 {
+		t.Fatalf("%v unmatched requests, expected 1", got)
+	}
+
+	before = count(metrics.UnmatchedRoute, metrics.OtherValue, "404", "")
+	request(t, "PROPFIND", "/metricstest/1", http.StatusNotFound)
+	if got := count(metrics.UnmatchedRoute, metrics.OtherValue, "404", "") - before; got != 1 {
+		t.Fatalf("%v requests of an unknown method, expected 1", got)
+	}
+	if got := count(metrics.UnmatchedRoute, "PROPFIND", "404", ""); got != 0 {
+		t.Fatalf("%v requests labelled with the unknown method", got)
+	}
+}
+
+// TestCardinality checks that the routes and business codes beyond the maximums are labelled other,
+// and that the known ones keep their labels.
+func TestCardinality(t *testing.T) {
+	request(t, "GET", "/metricstest/1", http.StatusOK)
+	request(t, "GET", "/metricstest/code/7", http.StatusOK)
+	maxRoutes, maxCodes := metrics.MaxRoutes, metrics.MaxCodes
+	defer func() {
+		metrics.MaxRoutes, metrics.MaxCodes = maxRoutes, maxCodes
+	}()
+	metrics.MaxRoutes, metrics.MaxCodes = 0, 0
+
+	before := count(metrics.OtherValue, "GET", "200", "")
+	request(t, "GET", "/metricstest/late", http.StatusOK)
+	if got := count(metrics.OtherValue, "GET", "200", "") - before; got != 1 {
+		t.Fatalf("%v requests of a new route labelled other, expected 1", got)
+	}
+	if got := count(route("late"), "GET", "200", ""); got != 0 {
+		t.Fatalf("%v requests labelled with the new route", got)
+	}
+
+	template := route("code")
+	before = count(template, "GET", "200", metrics.OtherValue)
+	request(t, "GET", "/metricstest/code/8", http.StatusOK)
+	if got := count(template, "GET", "200", metrics.OtherValue) - before; got != 1 {
+		t.Fatalf("%v requests of a new code labelled other, expected 1", got)
+	}
+	before = count(template, "GET", "200", "7")
+	request(t, "GET", "/metricstest/code/7", http.StatusOK)
+	if got := count(template, "GET", "200", "7") - before; got != 1 {
+		t.Fatalf("%v requests of a known code, expected 1", got)
+	}
+}
+
+// TestPanic checks that a panicking handler is counted as a 500 before the recover middleware answers.
+func TestPanic(t *testing.T) {
+	request(t, "GET", "/metricstest/panic", http.StatusInternalServerError)
+	template := route("panic")
+	if got := count(template, "GET", "500", ""); got != 1 {
+		t.Fatalf("%v panics counted as 500 on %s, expected 1", got, template)
+	}
+}
@@ -0,0 +1,430 @@
+// Package migration applies ordered, versioned up/down schema migrations written in Go or SQL.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Migration .
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+	UpSQL   []string
+	DownSQL []string
+}
+
+// Status .
+type Status struct {
+	Migration *Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Progress is the number of statements already executed by an interrupted migration, see schemaMigration.
+	Progress int
+}
+
+// schemaMigration is a row of the schema_migrations table.
+type schemaMigration struct {
+	Version   int64     `gorm:"primary_key;column:version;auto_increment:false"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+	// Progress counts the statements executed by a migration interrupted on MySQL, whose DDL commits implicitly:
+	// positive for an up migration, still pending, negative for a down migration, still applied. It is 0 otherwise.
+	Progress int `gorm:"column:progress"`
+}
+
+// TableName .
+func (obj *schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var registered []*Migration
+
+// Register registers a Go migration, usually from the init function of a migrations package.
+func Register(version int64, name string, up, down func(db *gorm.DB) error) {
+	registered = append(registered, &Migration{Version: version, Name: name, Up: up, Down: down})
+}
+
+// Migrator .
+type Migrator struct {
+	DB *gorm.DB
+	// Dir contains the SQL migrations, named <version>_<name>.up.sql and <version>_<name>.down.sql.
+	// It must exist, an empty Dir only applies the registered Go migrations.
+	Dir string
+	// DryRun prints what would be applied without changing the database.
+	DryRun bool
+	Out    io.Writer
+	// LockTimeout is how long to wait for another instance to finish migrating.
+	LockTimeout time.Duration
+}
+
+// New .
+func New(db *gorm.DB, dir string) *Migrator {
+	return &Migrator{DB: db, Dir: dir, Out: os.Stdout, LockTimeout: 30 * time.Second}
+}
+
+// FileName matches the SQL migration files, e.g. 20201019080000_add_goods_index.up.sql.
+// A file may be specific to a dialect, e.g. 1_baseline.mysql.up.sql, it replaces the common file on that dialect
+// and is ignored on the others.
+var FileName = regexp.MustCompile(`^(\d+)_(.+?)(?:\.(mysql|postgres|sqlite3))?\.(up|down)\.sql$`)
+
+// Load returns the registered Go migrations and the SQL migrations of Dir, ordered by version.
+func (m *Migrator) Load() ([]*Migration, error) {
+	byVersion := map[int64]*Migration{}
+	for _, item := range registered {
+		if _, ok := byVersion[item.Version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d", item.Version)
+		}
+		byVersion[item.Version] = item
+	}
+
+	var files []os.FileInfo
+	if m.Dir != "" {
+		//目录不存在时报错, 避免在错误的工作目录下误报没有待执行的迁移
+		var err error
+		if files, err = ioutil.ReadDir(m.Dir); err != nil {
+			return nil, fmt.Errorf("migrations directory: %v", err)
+		}
+	}
+	dialect := m.DB.Dialect().GetName()
+	sqlMigrations := map[int64]*Migration{}
+	specific := map[string]bool{}
+	for _, file := range files {
+		match := FileName.FindStringSubmatch(file.Name())
+		if match == nil || (match[3] != "" && match[3] != dialect) {
+			continue
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		item, ok := sqlMigrations[version]
+		if !ok {
+			if _, exist := byVersion[version]; exist {
+				return nil, fmt.Errorf("duplicate migration version %d", version)
+			}
+			item = &Migration{Version: version, Name: match[2]}
+			sqlMigrations[version] = item
+			byVersion[version] = item
+		}
+		//方言专用文件优先于通用文件
+		key := fmt.Sprintf("%d.%s", version, match[4])
+		if specific[key] && match[3] == "" {
+			continue
+		}
+		specific[key] = match[3] != ""
+		content, err := ioutil.ReadFile(filepath.Join(m.Dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if match[4] == "up" {
+			item.UpSQL = splitStatements(string(content))
+		} else {
+			item.DownSQL = splitStatements(string(content))
+		}
+	}
+
+	result := []*Migration{}
+	for _, item := range byVersion {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// Status returns every migration and whether it is applied.
+func (m *Migrator) Status() ([]Status, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	result := []Status{}
+	for _, item := range migrations {
+		row, ok := applied[item.Version]
+		result = append(result, Status{Migration: item, Applied: ok && row.Progress <= 0, AppliedAt: row.AppliedAt, Progress: row.Progress})
+	}
+	return result, nil
+}
+
+// Pending returns the migrations not applied yet.
+func (m *Migrator) Pending() ([]*Migration, error) {
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	result := []*Migration{}
+	for _, item := range status {
+		if !item.Applied {
+			result = append(result, item.Migration)
+		}
+	}
+	return result, nil
+}
+
+// Up applies at most limit pending migrations, all of them when limit is 0.
+func (m *Migrator) Up(limit int) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if !m.DryRun {
+		if err := m.DB.AutoMigrate(&schemaMigration{}).Error; err != nil {
+			return err
+		}
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(m.Out, "no pending migrations")
+	}
+	for _, item := range pending {
+		if err := m.apply(item, true); err != nil {
+			return fmt.Errorf("migration %d_%s up: %v", item.Version, item.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts at most limit applied migrations, the latest first.
+func (m *Migrator) Down(limit int) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if !m.DryRun {
+		if err := m.DB.AutoMigrate(&schemaMigration{}).Error; err != nil {
+			return err
+		}
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	for index := len(status) - 1; index >= 0 && limit > 0; index-- {
+		if !status[index].Applied {
+			continue
+		}
+		item := status[index].Migration
+		if err := m.apply(item, false); err != nil {
+			return fmt.Errorf("migration %d_%s down: %v", item.Version, item.Name, err)
+		}
+		limit--
+	}
+	return nil
+}
+
+// Create writes an empty pair of SQL migrations named with the current timestamp.
+func Create(dir, name string) (up, down string, err error) {
+	name = strings.ToLower(regexp.MustCompile(`[^A-Za-z0-9]+`).ReplaceAllString(name, "_"))
+	prefix := filepath.Join(dir, time.Now().Format("20060102150405")+"_"+name)
+	up, down = prefix+".up.sql", prefix+".down.sql"
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	if err = ioutil.WriteFile(up, []byte("-- "+name+" up\n"), 0644); err != nil {
+		return
+	}
+	err = ioutil.WriteFile(down, []byte("-- "+name+" down\n"), 0644)
+	return
+}
+
+func (m *Migrator) apply(item *Migration, up bool) error {
+	direction, statements, f := "down", item.DownSQL, item.Down
+	if up {
+		direction, statements, f = "up", item.UpSQL, item.Up
+	}
+	fmt.Fprintf(m.Out, "%s %d_%s\n", direction, item.Version, item.Name)
+	if m.DryRun {
+		for _, statement := range statements {
+			fmt.Fprintf(m.Out, "  %s;\n", statement)
+		}
+		return nil
+	}
+	if f == nil && statements == nil && !up {
+		return fmt.Errorf("irreversible migration")
+	}
+
+	if m.DB.Dialect().GetName() == "mysql" {
+		return m.applyStepwise(item, up)
+	}
+	tx := m.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	err := func() error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if f != nil {
+			if err := f(tx); err != nil {
+				return err
+			}
+		}
+		if up {
+			return tx.Create(&schemaMigration{Version: item.Version, Name: item.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&schemaMigration{Version: item.Version}).Error
+	}()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// applyStepwise applies a migration without a transaction, since the DDL of MySQL commits implicitly.
+// The progress is recorded after every statement, a failed migration resumes after the last executed statement.
+func (m *Migrator) applyStepwise(item *Migration, up bool) error {
+	statements, f := item.DownSQL, item.Down
+	if up {
+		statements, f = item.UpSQL, item.Up
+	}
+	rows, err := m.applied()
+	if err != nil {
+		return err
+	}
+	done := rows[item.Version].Progress
+	if !up {
+		done = -done
+	}
+	if done < 0 || done > len(statements) {
+		done = 0
+	}
+	if done > 0 {
+		fmt.Fprintf(m.Out, "  resuming after statement %d of %d\n", done, len(statements))
+	}
+	for index := done; index < len(statements); index++ {
+		if err := m.DB.Exec(statements[index]).Error; err != nil {
+			return fmt.Errorf("statement %d of %d, the previous ones are applied: %v", index+1, len(statements), err)
+		}
+		progress := index + 1
+		if !up {
+			progress = -progress
+		}
+		if err := m.DB.Save(&schemaMigration{Version: item.Version, Name: item.Name, AppliedAt: time.Now(), Progress: progress}).Error; err != nil {
+			return err
+		}
+	}
+	if f != nil {
+		if err := f(m.DB); err != nil {
+			return err
+		}
+	}
+	if up {
+		return m.DB.Save(&schemaMigration{Version: item.Version, Name: item.Name, AppliedAt: time.Now()}).Error
+	}
+	return m.DB.Delete(&schemaMigration{Version: item.Version}).Error
+}
+
+// applied returns the rows of schema_migrations by version, the table is created by Up and Down.
+func (m *Migrator) applied() (map[int64]schemaMigration, error) {
+	if !m.DB.HasTable(&schemaMigration{}) {
+		return map[int64]schemaMigration{}, nil
+	}
+	rows := []schemaMigration{}
+	if err := m.DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := map[int64]schemaMigration{}
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+const lockName = "schema_migrations"
+
+// lock prevents two instances from migrating concurrently, using the advisory locks of mysql and postgres.
+func (m *Migrator) lock() (unlock func(), err error) {
+	unlock = func() {}
+	if m.DryRun {
+		return
+	}
+	dialect := m.DB.Dialect().GetName()
+	if dialect != "mysql" && dialect != "postgres" {
+		return
+	}
+
+	ctx := context.Background()
+	conn, err := m.DB.DB().Conn(ctx)
+	if err != nil {
+		return
+	}
+	var acquired bool
+	switch dialect {
+	case "mysql":
+		var result int
+		err = conn.QueryRowContext(ctx, "SELECT COALESCE(GET_LOCK(?, ?), 0)", lockName, int(m.LockTimeout.Seconds())).Scan(&result)
+		acquired = result == 1
+	case "postgres":
+		ctx, cancel := context.WithTimeout(ctx, m.LockTimeout)
+		defer cancel()
+		_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", lockName)
+		acquired = err == nil
+	}
+	if err == nil && !acquired {
+		err = fmt.Errorf("another instance is migrating, lock '%s' not acquired within %s", lockName, m.LockTimeout)
+	}
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	unlock = func() {
+		if dialect == "mysql" {
+			var result int
+			conn.QueryRowContext(ctx, "SELECT COALESCE(RELEASE_LOCK(?), 0)", lockName).Scan(&result)
+		} else {
+			conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+		}
+		conn.Close()
+	}
+	return
+}
+
+// splitStatements splits a SQL file into statements, dropping comment lines.
+func splitStatements(content string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	result := []string{}
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";\n") {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if statement != "" {
+			result = append(result, statement)
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//内存数据库每个连接各自独立
+	db.DB().SetMaxOpenConns(1)
+	db.LogMode(false)
+	return db
+}
+
+// TestMissingDir checks that a wrong migrations directory is an error, not "no pending migrations".
+func TestMissingDir(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+	if _, err := New(db, "./missing").Pending(); err == nil {
+		t.Fatal("expected the missing directory to fail")
+	}
+}
+
+// TestStepwise checks that a migration interrupted part way, as the DDL of MySQL can't be rolled back,
+// resumes after its last executed statement.
+func TestStepwise(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+	dir, err := ioutil.TempDir("", "migration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	m := New(db, dir)
+	m.Out = ioutil.Discard
+	if err := db.AutoMigrate(&schemaMigration{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	write(t, dir, "1_tables.up.sql", `CREATE TABLE "first" ("id" integer);`+"\n"+`CREATE TABLE "second" ("id" integer;`)
+	write(t, dir, "1_tables.down.sql", `DROP TABLE "second";`+"\n"+`DROP TABLE "missing";`)
+	if err := m.applyStepwise(load(t, m), true); err == nil {
+		t.Fatal("expected the broken statement to fail")
+	}
+	checkStatus(t, m, false, 1)
+
+	//修正失败的语句后重新执行, 已执行的CREATE TABLE不再重复
+	write(t, dir, "1_tables.up.sql", `CREATE TABLE "first" ("id" integer);`+"\n"+`CREATE TABLE "second" ("id" integer);`)
+	if err := m.applyStepwise(load(t, m), true); err != nil {
+		t.Fatal(err)
+	}
+	checkStatus(t, m, true, 0)
+
+	if err := m.applyStepwise(load(t, m), false); err == nil {
+		t.Fatal("expected the missing table to fail")
+	}
+	checkStatus(t, m, true, -1)
+
+	write(t, dir, "1_tables.down.sql", `DROP TABLE "second";`+"\n"+`DROP TABLE "first";`)
+	if err := m.applyStepwise(load(t, m), false); err != nil {
+		t.Fatal(err)
+	}
+	checkStatus(t, m, false, 0)
+	if db.HasTable("first") || db.HasTable("second") {
+		t.Fatal("expected the tables dropped")
+	}
+}
+
+func write(t *testing.T, dir, name, content string) {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func load(t *testing.T, m *Migrator) *Migration {
+	migrations, err := m.Load()
+	if err != nil || len(migrations) != 1 {
+		t.Fatalf("loaded %d migrations: %v", len(migrations), err)
+	}
+	return migrations[0]
+}
+
+func checkStatus(t *testing.T, m *Migrator, applied bool, progress int) {
+	t.Helper()
+	status, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status[0].Applied != applied || status[0].Progress != progress {
+		t.Fatalf("status %+v, expected applied %t and progress %d", status[0], applied, progress)
+	}
+}
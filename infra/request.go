@@ -2,13 +2,23 @@
 package infra
 
 import (
+	"fmt"
 	"io/ioutil"
+	"strconv"
+	"strings"
 
 	"encoding/json"
 	"github.com/8treenet/freedom"
 	"gopkg.in/go-playground/validator.v9"
 )
 
+const (
+	// DefaultPageSize is used when the pageSize query parameter is absent.
+	DefaultPageSize = 20
+	// MaxPageSize bounds the pageSize query parameter.
+	MaxPageSize = 100
+)
+
 var validate *validator.Validate
 
 func init() {
@@ -24,6 +34,11 @@ func init() {
 	})
 }
 
+// Validate checks the validate tags of obj with the validator of Request, e.g. for imported rows.
+func Validate(obj interface{}) error {
+	return validate.Struct(obj)
+}
+
 // Request .
 type Request struct {
 	freedom.Infra
@@ -63,3 +78,79 @@ func (req *Request) ReadForm(obj interface{}) error {
 	}
 	return validate.Struct(obj)
 }
+
+// Paging is a page of a list as read by ReadPaging, repository.NewPagerOf turns it into a repository.Pager.
+type Paging struct {
+	Page     int
+	PageSize int
+	Sorts    []Sort
+}
+
+// Sort .
+type Sort struct {
+	Column string
+	Order  string // asc or desc
+}
+
+// ReadPaging reads and bounds the page, pageSize and sort query parameters.
+// sort is a comma separated column list, a leading '-' means descending, e.g. "-created,id".
+// Only columns listed in sortable are accepted, defaultSort is used when sort is absent.
+func (req *Request) ReadPaging(defaultSort string, sortable ...string) (Paging, error) {
+	ctx := req.Worker.IrisContext()
+	page, err := readPositiveInt(ctx.URLParam("page"), 1)
+	if err != nil {
+		return Paging{}, fmt.Errorf("page: %v", err)
+	}
+	pageSize, err := readPositiveInt(ctx.URLParam("pageSize"), DefaultPageSize)
+	if err != nil {
+		return Paging{}, fmt.Errorf("pageSize: %v", err)
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	sort := ctx.URLParam("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+	result := Paging{Page: page, PageSize: pageSize}
+	for _, column := range strings.Split(sort, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			continue
+		}
+		order := "asc"
+		if strings.HasPrefix(column, "-") {
+			order = "desc"
+			column = column[1:]
+		}
+		if !inStrings(column, sortable) {
+			return Paging{}, fmt.Errorf("sort: unsupported column '%s'", column)
+		}
+		result.Sorts = append(result.Sorts, Sort{Column: column, Order: order})
+	}
+	return result, nil
+}
+
+func readPositiveInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if result < 1 {
+		return 0, fmt.Errorf("must be greater than 0")
+	}
+	return result, nil
+}
+
+func inStrings(value string, list []string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
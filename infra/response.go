@@ -2,20 +2,32 @@
 package infra
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"encoding/json"
+	"github.com/8treenet/dump/infra/masking"
 	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/hero"
 )
 
 // JSONResponse .
+// ETag and LastModified are optional validators of Object, e.g. VersionETag(goods.Version) and goods.Updated,
+// sent on GET and HEAD responses. When ETag is empty a strong ETag of the serialized payload is used.
+// A compressed response carries the ETag with the suffix of its content coding, see EncodedETag.
 type JSONResponse struct {
-	Code        int
-	Error       error
-	contentType string
-	content     []byte
-	Object      interface{}
+	Code         int
+	Error        error
+	contentType  string
+	content      []byte
+	Object       interface{}
+	ETag         string
+	LastModified time.Time
 }
 
 // Dispatch This is the middleware for HTTP output.
@@ -27,17 +39,123 @@ func (jrep JSONResponse) Dispatch(ctx context.Context) {
 		Data  interface{} `json:"data,omitempty"`
 	}
 
-	repData.Data = jrep.Object
+	repData.Data = masking.Value(jrep.Object) //敏感字段脱敏
 	repData.Code = jrep.Code
 	if jrep.Error != nil {
 		repData.Error = jrep.Error.Error()
 	}
+	statusCode := 0
+	if errors.Is(jrep.Error, ErrPreconditionFailed) {
+		statusCode = http.StatusPreconditionFailed
+		if repData.Code == 0 {
+			repData.Code = statusCode
+		}
+	}
 	if repData.Error != "" && repData.Code == 0 {
 		repData.Code = 501
 	}
 	ctx.Values().Set("code", strconv.Itoa(repData.Code))
 
 	jrep.content, _ = json.Marshal(repData)
-	ctx.Values().Set("response", string(jrep.content))
-	hero.DispatchCommon(ctx, 0, jrep.contentType, jrep.content, nil, nil, true)
+	ctx.Values().Set("response", logContent(jrep.content))
+	ctx.Header("Vary", "Accept-Encoding")
+	encoding, encoder := acceptedEncoding(ctx, len(jrep.content))
+	//写请求的结果不是资源的表示, 只有GET/HEAD带验证器
+	method := ctx.Method()
+	validators := repData.Error == "" && (method == http.MethodGet || method == http.MethodHead)
+	if validators {
+		if jrep.ETag == "" {
+			jrep.ETag = ContentETag(jrep.content)
+		}
+		ctx.Header("ETag", EncodedETag(jrep.ETag, encoding))
+		if !jrep.LastModified.IsZero() {
+			ctx.Header("Last-Modified", jrep.LastModified.UTC().Format(http.TimeFormat))
+		}
+		if notModified(ctx, jrep.ETag, jrep.LastModified) {
+			ctx.StatusCode(http.StatusNotModified)
+			return
+		}
+	}
+	jrep.content, encoding = compressContent(jrep.content, encoding, encoder)
+	if encoding != "" {
+		ctx.Header("Content-Encoding", encoding)
+	} else if validators {
+		ctx.Header("ETag", jrep.ETag) //压缩失败时发送原文
+	}
+	hero.DispatchCommon(ctx, statusCode, jrep.contentType, jrep.content, nil, nil, true)
+}
+
+// Pager is the paging of an executed list query, e.g. a repository.Pager.
+type Pager interface {
+	Page() int
+	PageSize() int
+	TotalPage() int
+	TotalCount() int
+}
+
+// PageResponse wraps a page of items with its paging metadata.
+type PageResponse struct {
+	Code  int
+	Error error
+	Items interface{}
+	Pager Pager
+}
+
+// Dispatch This is the middleware for HTTP output.
+func (prep PageResponse) Dispatch(ctx context.Context) {
+	jrep := JSONResponse{Code: prep.Code, Error: prep.Error}
+	if prep.Error != nil {
+		jrep.Dispatch(ctx)
+		return
+	}
+
+	var data struct {
+		Items      interface{} `json:"items"`
+		Page       int         `json:"page,omitempty"`
+		PageSize   int         `json:"pageSize,omitempty"`
+		TotalPage  int         `json:"totalPage"`
+		TotalCount int         `json:"totalCount"`
+	}
+	data.Items = prep.Items
+	if prep.Pager != nil {
+		data.Page = prep.Pager.Page()
+		data.PageSize = prep.Pager.PageSize()
+		data.TotalPage = prep.Pager.TotalPage()
+		data.TotalCount = prep.Pager.TotalCount()
+	}
+
+	if link := prep.link(ctx); link != "" {
+		ctx.Header("Link", link)
+	}
+	jrep.Object = data
+	jrep.Dispatch(ctx)
+}
+
+// link returns the RFC 5988 Link header value.
+func (prep PageResponse) link(ctx context.Context) string {
+	if prep.Pager == nil || prep.Pager.Page() == 0 {
+		return ""
+	}
+	requestURL := *ctx.Request().URL
+	links := []string{}
+	add := func(rel string, page int) {
+		query := requestURL.Query()
+		query.Set("page", strconv.Itoa(page))
+		u := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", u.String(), rel))
+	}
+
+	page := prep.Pager.Page()
+	totalPage := prep.Pager.TotalPage()
+	add("first", 1)
+	if page > 1 {
+		add("prev", page-1)
+	}
+	if page < totalPage {
+		add("next", page+1)
+	}
+	if totalPage > 0 {
+		add("last", totalPage)
+	}
+	return strings.Join(links, ", ")
 }
@@ -0,0 +1,91 @@
+package infra
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/8treenet/dump/infra/masking"
+	"github.com/kataras/iris/v12/context"
+)
+
+// RowIterator yields rows one by one, e.g. repository.Iterator.
+type RowIterator interface {
+	Next() (row interface{}, ok bool, err error)
+}
+
+// StreamResponse encodes the rows of Rows incrementally as a JSON array.
+// The body is {"data":[...],"code":0,"error":""}, code and error are written last
+// so that an error raised while iterating can still be reported.
+type StreamResponse struct {
+	Rows RowIterator
+	// FlushRows is the number of rows written between flushes, 100 by default.
+	FlushRows int
+}
+
+// Dispatch This is the middleware for HTTP output.
+func (srep StreamResponse) Dispatch(ctx context.Context) {
+	if closer, ok := srep.Rows.(io.Closer); ok {
+		defer closer.Close()
+	}
+	flushRows := srep.FlushRows
+	if flushRows <= 0 {
+		flushRows = 100
+	}
+
+	ctx.ContentType("application/json")
+	ctx.Header("Vary", "Accept-Encoding")
+	var writer io.Writer = ctx.ResponseWriter()
+	if name, encoder := negotiateEncoding(ctx.GetHeader("Accept-Encoding")); encoder != nil {
+		if compressor, err := encoder(writer); err == nil {
+			ctx.Header("Content-Encoding", name)
+			defer compressor.Close()
+			writer = compressor
+		}
+	}
+	flush := func() {
+		if flusher, ok := writer.(interface{ Flush() error }); ok {
+			flusher.Flush()
+		}
+		if flusher, ok := ctx.ResponseWriter().(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+
+	code := 0
+	errText := ""
+	count := 0
+	io.WriteString(writer, `{"data":[`)
+	for {
+		row, ok, err := srep.Rows.Next()
+		if err != nil {
+			code = 501
+			errText = err.Error()
+			break
+		}
+		if !ok {
+			break
+		}
+		content, err := json.Marshal(masking.Value(row))
+		if err != nil {
+			code = 501
+			errText = err.Error()
+			break
+		}
+		if count > 0 {
+			io.WriteString(writer, ",")
+		}
+		writer.Write(content)
+		count++
+		if count%flushRows == 0 {
+			flush()
+		}
+	}
+	errContent, _ := json.Marshal(errText)
+	fmt.Fprintf(writer, `],"code":%d,"error":%s}`, code, errContent)
+
+	ctx.Values().Set("code", strconv.Itoa(code))
+	ctx.Values().Set("response", fmt.Sprintf("stream rows: %d, error: %s", count, errText))
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporters of app.toml.
+const (
+	ExporterDisabled = "disabled"
+	ExporterStdout   = "stdout"
+	ExporterOTLP     = "otlp"
+)
+
+// Exporters .
+var Exporters = []string{ExporterDisabled, ExporterStdout, ExporterOTLP}
+
+// NewStdoutExporter writes one JSON object per span to w, os.Stdout in app.toml.
+func NewStdoutExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// NewOTLPExporter posts the spans to an OpenTelemetry collector with OTLP/HTTP,
+// endpoint is the url of its traces, e.g. http://localhost:4318/v1/traces.
+func NewOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("trace endpoint '%s': expected an http or https url", endpoint)
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(target.Host), otlptracehttp.WithURLPath(target.Path)}
+	if target.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), options...)
+}
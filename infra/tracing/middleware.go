@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/8treenet/freedom/infra/requests"
+	iriscontext "github.com/kataras/iris/v12/context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header of the request id of the Trace middleware, recorded on the server spans.
+const RequestIDHeader = "x-request-id"
+
+// NewMiddleware starts the server span of every request, continuing the trace of its traceparent header.
+// The span is the one of the request context, worker.IrisContext().Request().Context().
+func NewMiddleware() iriscontext.Handler {
+	return func(ctx iriscontext.Context) {
+		parent := otel.GetTextMapPropagator().Extract(ctx.Request().Context(), propagation.HeaderCarrier(ctx.Request().Header))
+		attributes := []attribute.KeyValue{
+			attribute.String("http.method", ctx.Method()),
+			attribute.String("http.target", ctx.Path()),
+		}
+		if id := ctx.GetHeader(RequestIDHeader); id != "" {
+			attributes = append(attributes, attribute.String("http.request_id", id))
+		}
+		requestCtx, span := Start(parent, "HTTP "+ctx.Method(), trace.SpanKindServer, attributes...)
+		ctx.ResetRequest(ctx.Request().WithContext(requestCtx))
+
+		defer func() {
+			if route := ctx.GetCurrentRoute(); route != nil {
+				span.SetName("HTTP " + ctx.Method() + " " + route.Path())
+				span.SetAttributes(attribute.String("http.route", route.Path()))
+			}
+			//panic由Recover中间件处理, 这里只记录后继续抛出
+			if r := recover(); r != nil {
+				SetError(span, fmt.Errorf("panic: %v", r))
+				span.End()
+				panic(r)
+			}
+			status := ctx.GetStatusCode()
+			span.SetAttributes(attribute.Int("http.status_code", status))
+			if status >= http.StatusInternalServerError {
+				SetError(span, fmt.Errorf("status %d", status))
+			}
+			span.End()
+		}()
+		ctx.Next()
+	}
+}
+
+// StartClient starts the span of an outgoing request and sets its traceparent header,
+// e.g. ctx, span := tracing.StartClient(ctx, req.Header, "GET", url); defer span.End().
+func StartClient(ctx context.Context, header http.Header, method, url string) (context.Context, trace.Span) {
+	ctx, span := Start(ctx, "HTTP "+method, trace.SpanKindClient,
+		attribute.String("http.method", method),
+		attribute.String("http.url", url),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+	return ctx, span
+}
+
+// NewRequestsMiddleware records the client span of every request of the freedom requests package,
+// child of the span of the request context, and sends the traceparent header downstream.
+func NewRequestsMiddleware() requests.Handler {
+	return func(m requests.Middleware) {
+		req := m.GetRequest()
+		_, span := StartClient(m.Context(), req.Header, req.Method, req.URL.String())
+		m.Next()
+		resp := m.GetRespone()
+		if resp.Error != nil {
+			SetError(span, resp.Error)
+		} else {
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			if resp.StatusCode >= http.StatusInternalServerError {
+				SetError(span, fmt.Errorf("status %d", resp.StatusCode))
+			}
+		}
+		span.End()
+	}
+}
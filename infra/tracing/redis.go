@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextClient is a client cloned per request, e.g. *redis.Client or a client embedding it.
+type contextClient interface {
+	WithContext(ctx context.Context) *redis.Client
+}
+
+// Redis returns a client of the request whose commands and pipelines record a client span, child of the
+// span of ctx, e.g. tracing.Redis(worker.IrisContext().Request().Context(), repo.Redis()).
+// The commands of go-redis v6 carry no context, the spans are recorded by a clone of the client bound to ctx,
+// before the clone executes or forwards the command. Only the command names are recorded, never the arguments.
+// Clients without WithContext are returned as they are.
+func Redis(ctx context.Context, client redis.Cmdable) redis.Cmdable {
+	original, ok := client.(contextClient)
+	if !ok || ctx == nil {
+		return client
+	}
+	result := original.WithContext(ctx)
+	result.WrapProcess(func(process func(redis.Cmder) error) func(redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			_, span := Start(ctx, "redis "+cmd.Name(), trace.SpanKindClient,
+				attribute.String("db.system", "redis"),
+				attribute.String("db.operation", cmd.Name()),
+			)
+			err := process(cmd)
+			if err != redis.Nil {
+				SetError(span, err)
+			}
+			span.End()
+			return err
+		}
+	})
+	result.WrapProcessPipeline(func(process func([]redis.Cmder) error) func([]redis.Cmder) error {
+		return func(cmds []redis.Cmder) error {
+			_, span := Start(ctx, "redis pipeline", trace.SpanKindClient,
+				attribute.String("db.system", "redis"),
+				attribute.Int("db.redis.commands", len(cmds)),
+			)
+			err := process(cmds)
+			if err != redis.Nil {
+				SetError(span, err)
+			}
+			span.End()
+			return err
+		}
+	})
+	return result
+}
@@ -0,0 +1,123 @@
+// Package tracing records the OpenTelemetry spans of the requests, repository calls, Redis commands and
+// outgoing requests, propagated with the W3C traceparent header and sent by the exporter of Install.
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation is the name of the tracer of the spans.
+const instrumentation = "github.com/8treenet/dump"
+
+// ShutdownTimeout bounds the export of the remaining spans by Shutdown.
+var ShutdownTimeout = 5 * time.Second
+
+var (
+	mu       sync.RWMutex
+	provider *sdktrace.TracerProvider
+	//未安装导出器时不记录span, 但仍然传递上游的traceparent
+	noop = trace.NewNoopTracerProvider().Tracer(instrumentation)
+)
+
+func init() {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Install sets the exporter of the spans, sent in batches, nil disables tracing. The spans of the previous exporter are flushed to it.
+// serviceName is the service.name of the resource of the spans.
+func Install(exporter sdktrace.SpanExporter, serviceName string) {
+	Shutdown()
+	if exporter != nil {
+		install(sdktrace.WithBatcher(exporter), serviceName)
+	}
+}
+
+// InstallSync sets the exporter of the spans, sent one by one when they end,
+// e.g. tracing.InstallSync(tracetest.NewInMemoryExporter(), "test") in the tests.
+func InstallSync(exporter sdktrace.SpanExporter, serviceName string) {
+	Shutdown()
+	if exporter != nil {
+		install(sdktrace.WithSyncer(exporter), serviceName)
+	}
+}
+
+func install(processor sdktrace.TracerProviderOption, serviceName string) {
+	next := sdktrace.NewTracerProvider(
+		processor,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	mu.Lock()
+	provider = next
+	mu.Unlock()
+}
+
+// Enabled reports whether spans are recorded.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return provider != nil
+}
+
+// Flush exports the current batch of the ended spans now.
+func Flush() error {
+	mu.RLock()
+	current := provider
+	mu.RUnlock()
+	if current == nil {
+		return nil
+	}
+	return current.ForceFlush(context.Background())
+}
+
+// Shutdown exports the remaining spans and disables tracing.
+func Shutdown() {
+	mu.Lock()
+	current := provider
+	provider = nil
+	mu.Unlock()
+	if current == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := current.Shutdown(ctx); err != nil {
+		otel.Handle(err)
+	}
+}
+
+func tracer() trace.Tracer {
+	mu.RLock()
+	defer mu.RUnlock()
+	if provider == nil {
+		return noop
+	}
+	return provider.Tracer(instrumentation)
+}
+
+// Start starts a span, child of the span of ctx, or of its remote parent, or the root of a new trace.
+func Start(ctx context.Context, name string, kind trace.SpanKind, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+}
+
+// SetError records err on span and marks the span failed, a nil err is ignored.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
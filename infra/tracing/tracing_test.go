@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func installMemory() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	InstallSync(exporter, "test")
+	return exporter
+}
+
+// TestRedis checks that the commands of the clone are children of the span of its context,
+// not of the context of the original client.
+func TestRedis(t *testing.T) {
+	exporter := installMemory()
+	defer Install(nil, "")
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+
+	ctx, request := Start(context.Background(), "request", trace.SpanKindServer)
+	Redis(ctx, client).Get("key")
+	client.Get("untraced")
+	request.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, expected the command and the request", len(spans))
+	}
+	command := spans[0]
+	if command.Name != "redis get" || command.Parent.SpanID() != request.SpanContext().SpanID() {
+		t.Fatalf("unexpected span %s, parent %s", command.Name, command.Parent.SpanID())
+	}
+	if command.Status.Code.String() != "Error" {
+		t.Fatalf("expected the refused connection on the span, got %v", command.Status)
+	}
+}
+
+// TestStartClient checks that the traceparent of the client span is sent downstream.
+func TestStartClient(t *testing.T) {
+	exporter := installMemory()
+	defer Install(nil, "")
+	header := http.Header{}
+	_, span := StartClient(context.Background(), header, "GET", "http://localhost/goods/1")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, expected 1", len(spans))
+	}
+	expected := "00-" + spans[0].SpanContext.TraceID().String() + "-" + spans[0].SpanContext.SpanID().String() + "-01"
+	if header.Get("traceparent") != expected {
+		t.Fatalf("traceparent %q, expected %q", header.Get("traceparent"), expected)
+	}
+}
+
+// TestDisabled checks that nothing is recorded without an exporter.
+func TestDisabled(t *testing.T) {
+	Install(nil, "")
+	if Enabled() {
+		t.Fatal("expected tracing disabled")
+	}
+	_, span := Start(context.Background(), "request", trace.SpanKindServer)
+	if span.IsRecording() {
+		t.Fatal("expected a span without recording")
+	}
+	span.End()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/8treenet/dump/infra/fixture"
+	"github.com/8treenet/dump/server/conf"
+	"github.com/8treenet/freedom"
+	"github.com/kataras/iris/v12"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands of the server, serve is the default.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "run the HTTP server, --addr overrides listen_addr, --h2c serves http2 without TLS", serve},
+		{"migrate", "apply, revert, list or create schema migrations", migrate},
+		{"seed", "load the fixtures of --dir into the database", seed},
+		{"routes", "print every registered route", routes},
+		{"config", "print the effective configuration, 'config check' also validates it", config},
+		{"generate", "(re)generate the po structs and the repository helpers", generate},
+		{"dump", "export the po tables to sql, jsonl or csv files as a consistent snapshot", dumpTables},
+		{"restore", "replay a dump directory in dependency order", restoreTables},
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: server [command] [flags]\n\ncommands:")
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", command.name, command.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nconfiguration flags such as -app.listen_addr=:80 are accepted by every command.")
+}
+
+// config prints the effective configuration with secrets redacted, 'config check' exits non-zero on problems.
+func config(args []string) {
+	conf.Get().Print(os.Stdout)
+	if len(args) == 0 || args[0] != "check" {
+		return
+	}
+	if e := conf.Get().Validate(); e != nil {
+		for _, problem := range e.(*conf.ValidationError).Problems {
+			fmt.Fprintln(os.Stderr, "error:", problem)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("\nconfiguration ok")
+}
+
+// seed loads the fixtures into the database.
+func seed(args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	dir := flags.String("dir", siblingDir("fixtures"), "fixtures directory")
+	truncate := flags.Bool("truncate", false, "delete the existing rows of every table before loading it")
+	flags.Parse(args)
+
+	if !conf.Get().DB.Installed() {
+		exitOnError(fmt.Errorf("database is not configured, see db.toml"))
+	}
+	db := openDatabase("database", conf.Get().DB.Addr)
+	defer db.Close()
+	loader := fixture.New(db)
+	loader.Truncate = *truncate
+	counts, e := loader.LoadDir(*dir)
+	exitOnError(e)
+
+	tables := []string{}
+	for table := range counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("%-20s %d rows\n", table, counts[table])
+	}
+}
+
+// routes builds the application without listening and prints the routes of every controller.
+func routes(args []string) {
+	app := freedom.NewApplication()
+	liveness(app)
+	app.Run(iris.Raw(func() error {
+		for _, route := range app.Iris().GetRoutes() {
+			fmt.Printf("%-8s %-40s %s\n", route.Method, route.Path, route.MainHandlerName)
+		}
+		return nil
+	}), *conf.Get().App)
+}
+
+// siblingDir returns ./name when run from the server directory, ./server/name otherwise.
+func siblingDir(name string) string {
+	if info, e := os.Stat("./" + name); e == nil && info.IsDir() {
+		return "./" + name
+	}
+	return "./server/" + name
+}
@@ -2,43 +2,87 @@
 package conf
 
 import (
+	"fmt"
+	"os"
+	"reflect"
 	"runtime"
+	"sync/atomic"
 
 	"github.com/8treenet/freedom"
 )
 
 func init() {
-	cfg = &Configuration{
-		DB:    newDBConf(),
-		App:   newAppConf(),
-		Redis: newRedisConf(),
-	}
+	current.Store(load())
 }
 
-// Get .
+// Get returns the current configuration snapshot, it is swapped atomically by Reload.
 func Get() *Configuration {
-	return cfg
+	return current.Load().(*Configuration)
 }
 
-var cfg *Configuration
+var current atomic.Value
 
 // Configuration .
 type Configuration struct {
-	DB    *DBConf
-	App   *freedom.Configuration
-	Redis *RedisConf
+	DB        *DBConf
+	App       *freedom.Configuration
+	Redis     *RedisConf
+	Server    *AppConf
+	Listeners []*ListenerConf
+	Masking   *MaskingConf
+	loadErr   error
+}
+
+func load() *Configuration {
+	result := &Configuration{
+		DB:        newDBConf(),
+		App:       newAppConf(),
+		Redis:     newRedisConf(),
+		Listeners: newListenersConf(),
+		Masking:   newMaskingConf(),
+	}
+	result.loadErr = override(result, os.Args[1:])
+	var err error
+	result.Server, err = newServerConf(result.App.Other)
+	if result.loadErr == nil {
+		result.loadErr = err
+	}
+	return result
+}
+
+// AppConf is the typed view of the [other] section of app.toml.
+type AppConf struct {
+	ListenAddr               string `toml:"listen_addr"`
+	ServiceName              string `toml:"service_name"`
+	RepositoryRequestTimeout int    `toml:"repository_request_timeout"`
+	PrometheusListenAddr     string `toml:"prometheus_listen_addr"`
+	LoggerLevel              string `toml:"logger_level"`
+	ShutdownSecond           int    `toml:"shutdown_second"`
+	DrainSecond              int    `toml:"drain_second"`      // 关闭前 readyz 先失败的时间
+	ExportDir                string `toml:"export_dir"`
+	SlowQueryMS              int    `toml:"slow_query_ms"`     // 慢查询阈值, 0 关闭
+	SQLComment               bool   `toml:"sql_comment"`       // SQL 末尾附带请求id注释
+	TraceExporter            string `toml:"trace_exporter"`    // otlp, stdout or disabled
+	TraceEndpoint            string `toml:"trace_endpoint"`    // OTLP/HTTP 地址, 例如 http://localhost:4318/v1/traces
+	AdminEnabled             bool   `toml:"admin_enabled"`     // pprof等诊断接口的独立监听
+	AdminListenAddr          string `toml:"admin_listen_addr"` // 默认只监听本机
+	AdminToken               string `toml:"admin_token"`       // 非空时请求需携带 X-Admin-Token, /admin/ 路由为空时拒绝
 }
 
 // DBConf .
 type DBConf struct {
-	Addr            string `toml:"addr"`
-	MaxOpenConns    int    `toml:"max_open_conns"`
-	MaxIdleConns    int    `toml:"max_idle_conns"`
-	ConnMaxLifeTime int    `toml:"conn_max_life_time"`
+	Enabled         bool     `toml:"enabled"`
+	Driver          string   `toml:"driver"` // mysql, postgres or sqlite3
+	Addr            string   `toml:"addr"`
+	Replicas        []string `toml:"replicas"` // 只读副本
+	MaxOpenConns    int      `toml:"max_open_conns"`
+	MaxIdleConns    int      `toml:"max_idle_conns"`
+	ConnMaxLifeTime int      `toml:"conn_max_life_time"`
 }
 
 // RedisConf .
 type RedisConf struct {
+	Enabled            bool   `toml:"enabled"`
 	Addr               string `toml:"addr"`
 	Password           string `toml:"password"`
 	DB                 int    `toml:"db"`
@@ -56,18 +100,83 @@ func newAppConf() *freedom.Configuration {
 	result := freedom.DefaultConfiguration()
 	result.Other["listen_addr"] = ":8000"
 	result.Other["service_name"] = "default"
+	result.Other["admin_listen_addr"] = "127.0.0.1:6060"
 	freedom.Configure(&result, "app.toml", false)
 	return &result
 }
 
+func newServerConf(other map[string]interface{}) (result *AppConf, e error) {
+	result = &AppConf{}
+	value := reflect.ValueOf(result).Elem()
+	for index := 0; index < value.NumField(); index++ {
+		key := value.Type().Field(index).Tag.Get("toml")
+		item, ok := other[key]
+		if !ok {
+			continue
+		}
+		if err := setValue(value.Field(index), fmt.Sprint(item)); err != nil && e == nil {
+			e = fmt.Errorf("app.%s: %v", key, err)
+		}
+	}
+	return
+}
+
+// ListenerConf is one [[listeners]] entry of listeners.toml.
+type ListenerConf struct {
+	Name          string `toml:"name"`
+	Addr          string `toml:"addr"`
+	Mode          string `toml:"mode"` // http, h2c or tls
+	CertFile      string `toml:"cert_file"`
+	KeyFile       string `toml:"key_file"`
+	ClientCAFile  string `toml:"client_ca_file"` // 配置后要求客户端证书 (mTLS)
+	RedirectHTTPS bool   `toml:"redirect_https"`
+}
+
+// MaskingConf is the per environment masking of sensitive columns, see infra/masking.
+type MaskingConf struct {
+	Dump      bool              `toml:"dump"` // 导出数据时是否脱敏
+	Salt      string            `toml:"salt"`
+	Overrides map[string]string `toml:"overrides"`
+}
+
+// Installed reports whether the database is configured and enabled.
+func (c *DBConf) Installed() bool {
+	return c.Enabled && c.Addr != ""
+}
+
+// Installed reports whether Redis is configured and enabled.
+func (c *RedisConf) Installed() bool {
+	return c.Enabled && c.Addr != ""
+}
+
 func newDBConf() *DBConf {
-	result := &DBConf{}
+	result := &DBConf{Enabled: true, Driver: "mysql"}
 	freedom.Configure(result, "db.toml", false)
 	return result
 }
 
+func newListenersConf() []*ListenerConf {
+	result := struct {
+		Listeners []*ListenerConf `toml:"listeners"`
+	}{}
+	freedom.Configure(&result, "listeners.toml", false)
+	for _, listener := range result.Listeners {
+		if listener.Mode == "" {
+			listener.Mode = "http"
+		}
+	}
+	return result.Listeners
+}
+
+func newMaskingConf() *MaskingConf {
+	result := &MaskingConf{Dump: true, Overrides: map[string]string{}}
+	freedom.Configure(result, "masking.toml", false)
+	return result
+}
+
 func newRedisConf() *RedisConf {
 	result := &RedisConf{
+		Enabled:            true,
 		MaxRetries:         0,
 		PoolSize:           10 * runtime.NumCPU(),
 		ReadTimeout:        3,
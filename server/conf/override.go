@@ -0,0 +1,259 @@
+package conf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the configuration.
+//
+// Precedence, from lowest to highest:
+//  1. defaults in newAppConf, newDBConf and newRedisConf
+//  2. app.toml, db.toml and redis.toml
+//  3. environment variables, e.g. DUMP_DB_ADDR, DUMP_REDIS_POOL_SIZE, DUMP_APP_LISTEN_ADDR
+//  4. secret files named by a _FILE variable, e.g. DUMP_DB_ADDR_FILE=/run/secrets/db_addr
+//  5. command-line flags, e.g. -db.addr=..., --redis.pool_size 64, -app.listen_addr=:80
+//
+// The variable and flag names are the section ("db", "redis", "masking" or "app") followed by the toml key.
+// Lists such as db.replicas are comma separated, maps such as masking.overrides are comma separated key=value pairs.
+const EnvPrefix = "DUMP_"
+
+// override applies environment variables, secret files and command-line flags to a loaded configuration.
+func override(cfg *Configuration, args []string) error {
+	flags := parseFlags(args)
+	lookup := func(section, key string) (string, bool, error) {
+		name := strings.ToUpper(EnvPrefix + section + "_" + key)
+		value, ok := os.LookupEnv(name)
+		if file, fileOk := os.LookupEnv(name + "_FILE"); fileOk {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return "", false, fmt.Errorf("%s_FILE: %v", name, err)
+			}
+			value, ok = strings.TrimRight(string(content), "\r\n"), true
+		}
+		if flagValue, flagOk := flags[section+"."+key]; flagOk {
+			value, ok = flagValue, true
+		}
+		return value, ok, nil
+	}
+
+	if err := overrideStruct("db", cfg.DB, lookup); err != nil {
+		return err
+	}
+	if err := overrideStruct("redis", cfg.Redis, lookup); err != nil {
+		return err
+	}
+	if err := overrideStruct("masking", cfg.Masking, lookup); err != nil {
+		return err
+	}
+	return overrideOther(cfg.App.Other, flags, lookup)
+}
+
+type lookupFunc func(section, key string) (string, bool, error)
+
+// overrideStruct overrides the toml tagged fields of obj.
+func overrideStruct(section string, obj interface{}, lookup lookupFunc) error {
+	value := reflect.ValueOf(obj).Elem()
+	for index := 0; index < value.NumField(); index++ {
+		key := value.Type().Field(index).Tag.Get("toml")
+		if key == "" || key == "-" {
+			continue
+		}
+		str, ok, err := lookup(section, key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := setValue(value.Field(index), str); err != nil {
+			return fmt.Errorf("%s.%s: %v", section, key, err)
+		}
+	}
+	return nil
+}
+
+// overrideOther overrides the App "other" map, keeping the type of existing values.
+func overrideOther(other map[string]interface{}, flags map[string]string, lookup lookupFunc) error {
+	keys := map[string]bool{}
+	for key := range other {
+		keys[key] = true
+	}
+	envPrefix := EnvPrefix + "APP_"
+	for _, env := range os.Environ() {
+		name := strings.SplitN(env, "=", 2)[0]
+		if strings.HasPrefix(name, envPrefix) {
+			keys[strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(name, envPrefix), "_FILE"))] = true
+		}
+	}
+	for name := range flags {
+		if strings.HasPrefix(name, "app.") {
+			keys[strings.TrimPrefix(name, "app.")] = true
+		}
+	}
+
+	for key := range keys {
+		str, ok, err := lookup("app", key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		value, err := convertOther(other[key], str)
+		if err != nil {
+			return fmt.Errorf("app.%s: %v", key, err)
+		}
+		other[key] = value
+	}
+	return nil
+}
+
+func convertOther(current interface{}, str string) (interface{}, error) {
+	switch current.(type) {
+	case int64:
+		return strconv.ParseInt(str, 10, 64)
+	case int:
+		return strconv.Atoi(str)
+	case float64:
+		return strconv.ParseFloat(str, 64)
+	case bool:
+		return strconv.ParseBool(str)
+	}
+	return str, nil
+}
+
+func setValue(field reflect.Value, str string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		items := []string{}
+		for _, item := range strings.Split(str, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		if field.Type() != reflect.TypeOf(map[string]string{}) {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		items := map[string]string{}
+		for _, item := range strings.Split(str, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			pair := strings.SplitN(item, "=", 2)
+			if len(pair) != 2 {
+				return fmt.Errorf("expected key=value, got '%s'", item)
+			}
+			items[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// parseFlags collects "-section.key=value", "--section.key value" style flags.
+// A boolean flag may be bare, "-db.enabled" is "-db.enabled=true"; as with the flag package
+// it only takes the next argument as its value when that is "true" or "false".
+// Other arguments are left for the command line of the server.
+func parseFlags(args []string) map[string]string {
+	result, _ := splitFlags(args)
+	return result
+}
+
+// StripFlags returns args without the configuration flags, so that subcommands can parse the rest.
+func StripFlags(args []string) []string {
+	_, rest := splitFlags(args)
+	return rest
+}
+
+// sections are the flag prefixes and the types of their sections.
+var sections = map[string]reflect.Type{
+	"db":      reflect.TypeOf(DBConf{}),
+	"redis":   reflect.TypeOf(RedisConf{}),
+	"masking": reflect.TypeOf(MaskingConf{}),
+	"app":     reflect.TypeOf(AppConf{}),
+}
+
+// boolFlag reports whether the flag name is a boolean setting, e.g. db.enabled.
+func boolFlag(name string) bool {
+	pair := strings.SplitN(name, ".", 2)
+	if len(pair) != 2 {
+		return false
+	}
+	typ := sections[pair[0]]
+	if typ == nil {
+		return false
+	}
+	for index := 0; index < typ.NumField(); index++ {
+		if typ.Field(index).Tag.Get("toml") == pair[1] {
+			return typ.Field(index).Type.Kind() == reflect.Bool
+		}
+	}
+	return false
+}
+
+func splitFlags(args []string) (flags map[string]string, rest []string) {
+	flags = map[string]string{}
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		if !strings.HasPrefix(arg, "-") {
+			rest = append(rest, arg)
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		value := ""
+		hasValue := false
+		if pos := strings.Index(name, "="); pos >= 0 {
+			name, value, hasValue = name[:pos], name[pos+1:], true
+		}
+		if _, ok := sections[strings.SplitN(name, ".", 2)[0]]; !ok || !strings.Contains(name, ".") {
+			rest = append(rest, arg)
+			continue
+		}
+		switch {
+		case hasValue:
+		case boolFlag(name):
+			value = "true"
+			if index+1 < len(args) && (args[index+1] == "true" || args[index+1] == "false") {
+				index++
+				value = args[index]
+			}
+		case index+1 < len(args):
+			index++
+			value = args[index]
+		}
+		flags[name] = value
+	}
+	return
+}
@@ -0,0 +1,113 @@
+package conf
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/8treenet/freedom"
+)
+
+// ProfileENV is the environment variable naming the configuration directory, as read by freedom.Configure.
+const ProfileENV = "FREEDOM_PROJECT_CONFIG"
+
+var (
+	subscribers   []func(old, next *Configuration)
+	subscribersMu sync.Mutex
+	reloadMu      sync.Mutex
+)
+
+// Subscribe registers f to be called after every successful reload with the previous and the new configuration.
+func Subscribe(f func(old, next *Configuration)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, f)
+}
+
+// Reload re-reads the configuration files, environment and flags.
+// An invalid configuration is rejected and the current one is kept.
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	next := load()
+	if e := next.Validate(); e != nil {
+		return e
+	}
+	old := Get()
+	current.Store(next)
+
+	subscribersMu.Lock()
+	list := append([]func(old, next *Configuration){}, subscribers...)
+	subscribersMu.Unlock()
+	for _, f := range list {
+		f(old, next)
+	}
+	return nil
+}
+
+// Watch reloads the configuration on SIGHUP and whenever app.toml, db.toml or redis.toml change.
+// interval is how often the files are checked for modification.
+func Watch(interval time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	ticker := time.NewTicker(interval)
+	modified := modTimes()
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				freedom.Logger().Info("SIGHUP received, reloading configuration")
+			case <-ticker.C:
+				next := modTimes()
+				if equalModTimes(modified, next) {
+					continue
+				}
+				modified = next
+				freedom.Logger().Info("configuration files changed, reloading configuration")
+			}
+			if e := Reload(); e != nil {
+				freedom.Logger().Errorf("reload configuration rejected, keeping the current one: %v", e)
+				continue
+			}
+			freedom.Logger().Info("configuration reloaded")
+		}
+	}()
+}
+
+// dir returns the configuration directory.
+func dir() string {
+	if path := os.Getenv(ProfileENV); path != "" {
+		return path
+	}
+	if info, err := os.Stat("./conf"); err == nil && info.IsDir() {
+		return "./conf"
+	}
+	return "./server/conf"
+}
+
+func modTimes() map[string]time.Time {
+	result := map[string]time.Time{}
+	for _, name := range []string{"app.toml", "db.toml", "redis.toml", "listeners.toml", "masking.toml"} {
+		if info, err := os.Stat(filepath.Join(dir(), name)); err == nil {
+			result[name] = info.ModTime()
+		}
+	}
+	return result
+}
+
+func equalModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, modTime := range a {
+		if !modTime.Equal(b[name]) {
+			return false
+		}
+	}
+	return true
+}
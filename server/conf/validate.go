@@ -0,0 +1,314 @@
+package conf
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const redacted = "******"
+
+var (
+	loggerLevels = []string{"fatal", "error", "warn", "info", "debug"}
+	dbDrivers    = []string{"mysql", "postgres", "sqlite3"}
+	listenModes  = []string{"http", "h2c", "tls"}
+	maskActions  = []string{"keep", "mask", "hash", "drop", "fake"}
+	traceExports = []string{"disabled", "stdout", "otlp"}
+	secretKeys   = []string{"admin_token"}
+)
+
+// ValidationError lists every problem found by Validate.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error .
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the whole configuration and reports all problems at once.
+func (c *Configuration) Validate() error {
+	problems := []string{}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	if c.loadErr != nil {
+		add("%v", c.loadErr)
+	}
+
+	server := c.Server
+	if server.ListenAddr == "" {
+		add("app.listen_addr is required")
+	} else if _, _, err := net.SplitHostPort(server.ListenAddr); err != nil {
+		add("app.listen_addr: %v", err)
+	}
+	if server.ServiceName == "" {
+		add("app.service_name is required")
+	}
+	if server.PrometheusListenAddr != "" {
+		if _, _, err := net.SplitHostPort(server.PrometheusListenAddr); err != nil {
+			add("app.prometheus_listen_addr: %v", err)
+		}
+	}
+	if server.LoggerLevel != "" && !inStrings(server.LoggerLevel, loggerLevels) {
+		add("app.logger_level: unknown level '%s', expected one of %s", server.LoggerLevel, strings.Join(loggerLevels, ", "))
+	}
+	if server.RepositoryRequestTimeout < 0 {
+		add("app.repository_request_timeout must not be negative")
+	}
+	if server.ShutdownSecond < 0 {
+		add("app.shutdown_second must not be negative")
+	}
+	if server.DrainSecond < 0 {
+		add("app.drain_second must not be negative")
+	}
+	if server.SlowQueryMS < 0 {
+		add("app.slow_query_ms must not be negative")
+	}
+	if server.TraceExporter != "" && !inStrings(server.TraceExporter, traceExports) {
+		add("app.trace_exporter: unknown exporter '%s', expected one of %s", server.TraceExporter, strings.Join(traceExports, ", "))
+	}
+	if server.TraceExporter == "otlp" {
+		if endpoint, err := url.Parse(server.TraceEndpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			add("app.trace_endpoint: expected an http or https url, got '%s'", server.TraceEndpoint)
+		}
+	}
+	if server.AdminEnabled {
+		if host, _, err := net.SplitHostPort(server.AdminListenAddr); err != nil {
+			add("app.admin_listen_addr: %v", err)
+		} else if !loopback(host) && server.AdminToken == "" {
+			add("app.admin_token is required when app.admin_listen_addr is not a loopback address")
+		}
+	}
+
+	if !inStrings(c.DB.Driver, dbDrivers) {
+		add("db.driver: unknown driver '%s', expected one of %s", c.DB.Driver, strings.Join(dbDrivers, ", "))
+	}
+	if c.DB.Driver == "mysql" {
+		if c.DB.Addr != "" {
+			if _, err := mysql.ParseDSN(c.DB.Addr); err != nil {
+				add("db.addr: %v", err)
+			}
+		}
+		for index, replica := range c.DB.Replicas {
+			if _, err := mysql.ParseDSN(replica); err != nil {
+				add("db.replicas[%d]: %v", index, err)
+			}
+		}
+	}
+	if c.DB.MaxOpenConns < 0 {
+		add("db.max_open_conns must not be negative")
+	}
+	if c.DB.MaxIdleConns < 0 {
+		add("db.max_idle_conns must not be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		add("db.max_idle_conns must not be greater than db.max_open_conns")
+	}
+	if c.DB.ConnMaxLifeTime < 0 {
+		add("db.conn_max_life_time must not be negative")
+	}
+
+	if c.Redis.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+			add("redis.addr: %v", err)
+		}
+	}
+	if c.Redis.DB < 0 {
+		add("redis.db must not be negative")
+	}
+	for key, value := range map[string]int{
+		"max_retries":          c.Redis.MaxRetries,
+		"pool_size":            c.Redis.PoolSize,
+		"read_timeout":         c.Redis.ReadTimeout,
+		"write_timeout":        c.Redis.WriteTimeout,
+		"idle_timeout":         c.Redis.IdleTimeout,
+		"idle_check_frequency": c.Redis.IdleCheckFrequency,
+		"max_conn_age":         c.Redis.MaxConnAge,
+		"pool_timeout":         c.Redis.PoolTimeout,
+	} {
+		if value < 0 {
+			add("redis.%s must not be negative", key)
+		}
+	}
+
+	hasTLS, addrs := false, map[string]bool{}
+	for index, listener := range c.Listeners {
+		prefix := fmt.Sprintf("listeners[%d]", index)
+		if listener.Addr == "" {
+			add("%s.addr is required", prefix)
+		} else if _, _, err := net.SplitHostPort(listener.Addr); err != nil {
+			add("%s.addr: %v", prefix, err)
+		} else if addrs[listener.Addr] {
+			add("%s.addr: duplicate address '%s'", prefix, listener.Addr)
+		}
+		addrs[listener.Addr] = true
+		if !inStrings(listener.Mode, listenModes) {
+			add("%s.mode: unknown mode '%s', expected one of %s", prefix, listener.Mode, strings.Join(listenModes, ", "))
+		}
+		if listener.Mode == "tls" {
+			hasTLS = true
+			if listener.CertFile == "" || listener.KeyFile == "" {
+				add("%s: tls requires cert_file and key_file", prefix)
+			}
+			for key, file := range map[string]string{"cert_file": listener.CertFile, "key_file": listener.KeyFile, "client_ca_file": listener.ClientCAFile} {
+				if file == "" {
+					continue
+				}
+				if _, err := os.Stat(file); err != nil {
+					add("%s.%s: %v", prefix, key, err)
+				}
+			}
+		} else if listener.ClientCAFile != "" {
+			add("%s.client_ca_file requires mode tls", prefix)
+		}
+		if listener.RedirectHTTPS && listener.Mode == "tls" {
+			add("%s.redirect_https is only valid on http and h2c listeners", prefix)
+		}
+	}
+	for index, listener := range c.Listeners {
+		if listener.RedirectHTTPS && !hasTLS {
+			add("listeners[%d].redirect_https requires a tls listener", index)
+		}
+	}
+
+	for key, action := range c.Masking.Overrides {
+		if !strings.Contains(key, ".") {
+			add("masking.overrides: key '%s' must be table.column", key)
+		}
+		if !inStrings(action, maskActions) {
+			add("masking.overrides.%s: unknown action '%s', expected one of %s", key, action, strings.Join(maskActions, ", "))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return &ValidationError{Problems: problems}
+}
+
+// Print writes the effective merged configuration with secrets redacted.
+func (c *Configuration) Print(w io.Writer) {
+	fmt.Fprintln(w, "[other]")
+	keys := []string{}
+	for key := range c.App.Other {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value, ok := c.App.Other[key].(string); ok {
+			if inStrings(key, secretKeys) && value != "" {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s = %q\n", key, value)
+			continue
+		}
+		fmt.Fprintf(w, "%s = %v\n", key, c.App.Other[key])
+	}
+
+	fmt.Fprintln(w, "\n[db]")
+	fmt.Fprintf(w, "enabled = %t\n", c.DB.Enabled)
+	fmt.Fprintf(w, "driver = %q\n", c.DB.Driver)
+	fmt.Fprintf(w, "addr = %q\n", RedactDSN(c.DB.Addr))
+	replicas := []string{}
+	for _, replica := range c.DB.Replicas {
+		replicas = append(replicas, fmt.Sprintf("%q", RedactDSN(replica)))
+	}
+	fmt.Fprintf(w, "replicas = [%s]\n", strings.Join(replicas, ", "))
+	fmt.Fprintf(w, "max_open_conns = %d\n", c.DB.MaxOpenConns)
+	fmt.Fprintf(w, "max_idle_conns = %d\n", c.DB.MaxIdleConns)
+	fmt.Fprintf(w, "conn_max_life_time = %d\n", c.DB.ConnMaxLifeTime)
+
+	password := ""
+	if c.Redis.Password != "" {
+		password = redacted
+	}
+	fmt.Fprintln(w, "\n[redis]")
+	fmt.Fprintf(w, "enabled = %t\n", c.Redis.Enabled)
+	fmt.Fprintf(w, "addr = %q\n", c.Redis.Addr)
+	fmt.Fprintf(w, "password = %q\n", password)
+	fmt.Fprintf(w, "db = %d\n", c.Redis.DB)
+	fmt.Fprintf(w, "max_retries = %d\n", c.Redis.MaxRetries)
+	fmt.Fprintf(w, "pool_size = %d\n", c.Redis.PoolSize)
+	fmt.Fprintf(w, "read_timeout = %d\n", c.Redis.ReadTimeout)
+	fmt.Fprintf(w, "write_timeout = %d\n", c.Redis.WriteTimeout)
+	fmt.Fprintf(w, "idle_timeout = %d\n", c.Redis.IdleTimeout)
+	fmt.Fprintf(w, "idle_check_frequency = %d\n", c.Redis.IdleCheckFrequency)
+	fmt.Fprintf(w, "max_conn_age = %d\n", c.Redis.MaxConnAge)
+	fmt.Fprintf(w, "pool_timeout = %d\n", c.Redis.PoolTimeout)
+
+	salt := ""
+	if c.Masking.Salt != "" {
+		salt = redacted
+	}
+	fmt.Fprintln(w, "\n[masking]")
+	fmt.Fprintf(w, "dump = %t\n", c.Masking.Dump)
+	fmt.Fprintf(w, "salt = %q\n", salt)
+	overrides := []string{}
+	for key := range c.Masking.Overrides {
+		overrides = append(overrides, key)
+	}
+	sort.Strings(overrides)
+	fmt.Fprintln(w, "[masking.overrides]")
+	for _, key := range overrides {
+		fmt.Fprintf(w, "%q = %q\n", key, c.Masking.Overrides[key])
+	}
+
+	for _, listener := range c.Listeners {
+		fmt.Fprintln(w, "\n[[listeners]]")
+		fmt.Fprintf(w, "name = %q\n", listener.Name)
+		fmt.Fprintf(w, "addr = %q\n", listener.Addr)
+		fmt.Fprintf(w, "mode = %q\n", listener.Mode)
+		fmt.Fprintf(w, "cert_file = %q\n", listener.CertFile)
+		fmt.Fprintf(w, "key_file = %q\n", listener.KeyFile)
+		fmt.Fprintf(w, "client_ca_file = %q\n", listener.ClientCAFile)
+		fmt.Fprintf(w, "redirect_https = %t\n", listener.RedirectHTTPS)
+	}
+}
+
+var (
+	dsnPassword     = regexp.MustCompile(`^([^:@/]*):([^@]*)@`)
+	keywordPassword = regexp.MustCompile(`(password=)('[^']*'|\S*)`)
+)
+
+// RedactDSN hides the password of a mysql, postgres or URL style database DSN.
+func RedactDSN(dsn string) string {
+	if strings.Contains(dsn, "://") {
+		if u, err := url.Parse(dsn); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), redacted)
+				return strings.Replace(u.String(), url.QueryEscape(redacted), redacted, 1)
+			}
+		}
+		return dsn
+	}
+	dsn = keywordPassword.ReplaceAllString(dsn, "${1}"+redacted)
+	return dsnPassword.ReplaceAllString(dsn, "$1:"+redacted+"@")
+}
+
+// loopback reports whether host only accepts local connections, e.g. 127.0.0.1 or localhost.
+func loopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func inStrings(value string, list []string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
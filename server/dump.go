@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/8treenet/dump/domain/po"
+	"github.com/8treenet/dump/infra/dump"
+	"github.com/8treenet/dump/server/conf"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	//按依赖顺序注册, 恢复时先写入被依赖的表。
+	dump.Register(
+		&po.Admin{},
+		&po.User{},
+		&po.Goods{},
+		&po.Product{},
+		&po.Albums{},
+		&po.Cart{},
+		&po.Order{},
+		&po.OrderDetail{},
+		&po.OrderLog{},
+		&po.Delivery{},
+		&po.Dump{},
+		&po.TestUsers{},
+		&po.TestEmails{},
+	)
+}
+
+// whereFlag collects repeated -where table:condition flags.
+type whereFlag map[string]string
+
+func (w whereFlag) String() string {
+	return fmt.Sprint(map[string]string(w))
+}
+
+func (w whereFlag) Set(value string) error {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected table:condition, e.g. order:status = 'paid'")
+	}
+	w[parts[0]] = parts[1]
+	return nil
+}
+
+// dumpTables exports the registered tables as a consistent snapshot.
+func dumpTables(args []string) {
+	where := whereFlag{}
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	dir := flags.String("dir", "dump-"+time.Now().Format("20060102150405"), "output directory")
+	format := flags.String("format", dump.FormatSQL, "sql, jsonl or csv")
+	tables := flags.String("tables", "", "comma separated tables, defaults to every registered table")
+	chunk := flags.Int("chunk", 1000, "rows read per query")
+	mask := flags.Bool("mask", conf.Get().Masking.Dump, "apply the masking policies, defaults to dump of masking.toml")
+	flags.Var(where, "where", "filter of a table as table:condition, may be repeated")
+	flags.Parse(args)
+
+	installMasking(conf.Get())
+	db := openDumpDatabase()
+	defer db.Close()
+	dumper := dump.New(db)
+	dumper.Mask = *mask
+	dumper.Format = *format
+	dumper.ChunkSize = *chunk
+	dumper.Where = where
+	if conf.Get().DB.Driver == "mysql" {
+		//mysql的时间没有时区, 按DSN的loc写出, 与驱动读回时一致
+		config, e := mysql.ParseDSN(conf.Get().DB.Addr)
+		exitOnError(e)
+		dumper.Location = config.Loc
+	}
+	names := []string{}
+	if *tables != "" {
+		names = strings.Split(*tables, ",")
+	}
+	counts, e := dumper.DumpDir(*dir, names...)
+	exitOnError(e)
+	printCounts(counts)
+	fmt.Println("dumped to", *dir)
+}
+
+// restoreTables replays a dump directory in dependency order.
+func restoreTables(args []string) {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	dir := flags.String("dir", "", "dump directory")
+	truncate := flags.Bool("truncate", false, "delete the existing rows of every restored table first")
+	batch := flags.Int("batch", 500, "jsonl and csv rows inserted per batch")
+	flags.Parse(args)
+	if *dir == "" {
+		exitOnError(fmt.Errorf("restore requires -dir"))
+	}
+
+	db := openDumpDatabase()
+	defer db.Close()
+	restorer := dump.NewRestorer(db)
+	restorer.Truncate = *truncate
+	restorer.BatchSize = *batch
+	counts, e := restorer.RestoreDir(*dir)
+	printCounts(counts)
+	exitOnError(e)
+}
+
+func openDumpDatabase() *gorm.DB {
+	if !conf.Get().DB.Installed() {
+		exitOnError(fmt.Errorf("database is not configured, see db.toml"))
+	}
+	return openDatabase("database", conf.Get().DB.Addr)
+}
+
+// printCounts prints the rows per table in dependency order.
+func printCounts(counts map[string]int) {
+	for _, table := range dump.Tables() {
+		if count, ok := counts[table]; ok {
+			fmt.Printf("%-20s %d rows\n", table, count)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/8treenet/dump/infra/generator"
+	"github.com/8treenet/dump/server/conf"
+)
+
+// generate runs the generate subcommand, it (re)generates the po structs and the repository helpers.
+func generate(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	from := flags.String("from", "db", "schema source, 'db' introspects the configured database, 'migrations' replays the SQL migrations")
+	dir := flags.String("dir", migrationsDir(), "SQL migrations directory")
+	dialect := flags.String("dialect", conf.Get().DB.Driver, "dialect of the SQL migrations replayed by -from migrations")
+	only := flags.String("tables", "", "comma separated tables to generate, every table by default; the repository helpers only cover them")
+	root := flags.String("root", projectRoot(), "project root directory")
+	check := flags.Bool("check", false, "fail if the committed code is stale instead of writing it")
+	flags.Parse(args)
+
+	var tables []*generator.Table
+	var e error
+	switch *from {
+	case "db":
+		if !conf.Get().DB.Installed() {
+			exitOnError(fmt.Errorf("database is not configured, see db.toml"))
+		}
+		db := openDatabase("database", conf.Get().DB.Addr)
+		defer db.Close()
+		tables, e = generator.FromDB(db.DB(), conf.Get().DB.Driver)
+	case "migrations":
+		tables, e = generator.FromMigrations(*dir, *dialect)
+	default:
+		e = fmt.Errorf("unknown schema source '%s'", *from)
+	}
+	exitOnError(e)
+	if *only != "" {
+		tables, e = generator.Select(tables, strings.Split(*only, ","))
+		exitOnError(e)
+	}
+	if len(tables) == 0 {
+		exitOnError(fmt.Errorf("no tables found"))
+	}
+
+	g := &generator.Generator{
+		PODir:          filepath.Join(*root, "domain", "po"),
+		RepositoryFile: filepath.Join(*root, "adapter", "repository", "generate.go"),
+		Check:          *check,
+	}
+	changed, e := g.Generate(tables)
+	exitOnError(e)
+	for _, name := range changed {
+		if *check {
+			fmt.Println("stale", name)
+			continue
+		}
+		fmt.Println("generated", name)
+	}
+	if *check && len(changed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// projectRoot returns .. when run from the server directory, . otherwise.
+func projectRoot() string {
+	if info, e := os.Stat("./migrations"); e == nil && info.IsDir() {
+		return ".."
+	}
+	return "."
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/8treenet/dump/server/conf"
+	"github.com/8treenet/freedom"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/host"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// listenerShutdownTimeout bounds how long the other listeners drain once one of them stopped.
+const listenerShutdownTimeout = 10 * time.Second
+
+// listenerRunner serves every listener as a host of the same iris application. The hosts run in one group:
+// the first one that stops, e.g. on a listen error, shuts the others down, and the runner returns once all stopped.
+// A shutdown of the application stops every host too, since they are all registered by NewHost.
+func listenerRunner(app freedom.Application, listeners []*conf.ListenerConf) (iris.Runner, error) {
+	configs := make([]*tls.Config, len(listeners))
+	for index, listener := range listeners {
+		if listener.Mode == "tls" {
+			config, e := newTLSConfig(listener)
+			if e != nil {
+				return nil, fmt.Errorf("listener %s: %v", listener.Addr, e)
+			}
+			configs[index] = config
+		}
+		app.Logger().Infof("listener %s, addr: %s, mode: %s", listener.Name, listener.Addr, listener.Mode)
+	}
+
+	return func(irisApp *iris.Application) error {
+		hosts := make([]*host.Supervisor, len(listeners))
+		for index, listener := range listeners {
+			server := &http.Server{Addr: listener.Addr}
+			if listener.Mode == "h2c" {
+				server.Handler = h2c.NewHandler(irisApp, &http2.Server{}) //http2 h2c 服务
+			}
+			hosts[index] = irisApp.NewHost(server)
+		}
+
+		errs := make(chan error, len(hosts))
+		for index := range hosts {
+			go func(su *host.Supervisor, config *tls.Config) {
+				errs <- serveHost(su, config)
+			}(hosts[index], configs[index])
+		}
+		e := <-errs
+		ctx, cancel := context.WithTimeout(context.Background(), listenerShutdownTimeout)
+		defer cancel()
+		for _, su := range hosts {
+			su.Shutdown(ctx)
+		}
+		for range hosts[1:] {
+			<-errs
+		}
+		return e
+	}, nil
+}
+
+// serveHost serves HTTP/1.1 and HTTP/2 over TLS when config is set, h2 is negotiated by ALPN.
+func serveHost(su *host.Supervisor, config *tls.Config) error {
+	if config == nil {
+		return su.ListenAndServe()
+	}
+	ln, e := net.Listen("tcp", su.Server.Addr)
+	if e != nil {
+		return e
+	}
+	return su.Serve(tls.NewListener(ln, config))
+}
+
+func newTLSConfig(listener *conf.ListenerConf) (*tls.Config, error) {
+	reloader := &certReloader{certFile: listener.CertFile, keyFile: listener.KeyFile}
+	if _, e := reloader.GetCertificate(nil); e != nil {
+		return nil, e
+	}
+	config := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+		MinVersion:     tls.VersionTLS12,
+	}
+	if listener.ClientCAFile == "" {
+		return config, nil
+	}
+
+	//双向认证, 客户端必须提供由 client_ca_file 签发的证书。
+	pem, e := ioutil.ReadFile(listener.ClientCAFile)
+	if e != nil {
+		return nil, e
+	}
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", listener.ClientCAFile)
+	}
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
+
+// certReloader reloads the certificate and key once either file changes, checking at most once per second.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTime  time.Time
+	checked  time.Time
+}
+
+// GetCertificate .
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert != nil && time.Since(r.checked) < time.Second {
+		return r.cert, nil
+	}
+	r.checked = time.Now()
+
+	modTime := time.Time{}
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, e := os.Stat(file)
+		if e != nil {
+			return r.loaded(e)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return r.cert, nil
+	}
+
+	cert, e := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if e != nil {
+		return r.loaded(e)
+	}
+	if r.cert != nil {
+		freedom.Logger().Infof("tls certificate %s reloaded", r.certFile)
+	}
+	r.cert, r.modTime = &cert, modTime
+	return r.cert, nil
+}
+
+// loaded keeps serving the previous certificate when a reload fails, e.g. while the files are being replaced.
+func (r *certReloader) loaded(e error) (*tls.Certificate, error) {
+	if r.cert == nil {
+		return nil, e
+	}
+	freedom.Logger().Warnf("tls certificate %s reload failed, keep the previous one: %v", r.certFile, e)
+	return r.cert, nil
+}
+
+// redirectHTTPS redirects requests of the listeners with redirect_https to the first tls listener.
+// It returns nil when no listener redirects.
+func redirectHTTPS(listeners []*conf.ListenerConf) iris.Handler {
+	ports := map[string]bool{}
+	httpsPort := ""
+	for _, listener := range listeners {
+		_, port, _ := net.SplitHostPort(listener.Addr)
+		if listener.RedirectHTTPS {
+			ports[port] = true
+		}
+		if listener.Mode == "tls" && httpsPort == "" {
+			httpsPort = port
+		}
+	}
+	if len(ports) == 0 || httpsPort == "" {
+		return nil
+	}
+
+	//探活接口不重定向, 负载均衡可以继续使用 http 端口检查。
+	exempt := map[string]bool{"/ping": true, "/healthz": true, "/readyz": true}
+	return func(ctx freedom.Context) {
+		request := ctx.Request()
+		if request.TLS != nil || exempt[ctx.Path()] || !ports[localPort(request)] {
+			ctx.Next()
+			return
+		}
+		host, _, e := net.SplitHostPort(request.Host)
+		if e != nil {
+			host = request.Host
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		status := http.StatusPermanentRedirect
+		if request.Method == http.MethodGet || request.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		ctx.Redirect("https://"+host+request.RequestURI, status)
+	}
+}
+
+func localPort(request *http.Request) string {
+	addr, ok := request.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return ""
+	}
+	_, port, _ := net.SplitHostPort(addr.String())
+	return port
+}
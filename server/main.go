@@ -2,91 +2,361 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	_ "github.com/8treenet/dump/adapter/controller" //引入输入适配器 http路由
-	_ "github.com/8treenet/dump/adapter/repository" //引入输出适配器 repository资源库
+	"github.com/8treenet/dump/adapter/repository"   //引入输出适配器 repository资源库
+	"github.com/8treenet/dump/infra/admin"
+	"github.com/8treenet/dump/infra/export"
+	"github.com/8treenet/dump/infra/health"
+	"github.com/8treenet/dump/infra/masking"
+	"github.com/8treenet/dump/infra/metrics"
+	"github.com/8treenet/dump/infra/migration"
+	"github.com/8treenet/dump/infra/tracing"
 	"github.com/8treenet/dump/server/conf"
+	_ "github.com/8treenet/dump/server/migrations" //引入数据库迁移
 	"github.com/8treenet/freedom"
 	"github.com/8treenet/freedom/infra/requests"
 	"github.com/8treenet/freedom/middleware"
 	"github.com/go-redis/redis"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/kataras/iris/v12"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
 	"time"
 )
 
+var (
+	gormDB      *gorm.DB
+	replicaDBs  []*gorm.DB
+	redisClient *swappableRedis
+)
+
 func main() {
-	app := freedom.NewApplication()
-	/*
-		installDatabase(app) //安装数据库
-		installRedis(app) //安装redis
+	name, args := "serve", conf.StripFlags(os.Args[1:])
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	for _, command := range commands {
+		if command.name == name {
+			command.run(args)
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+// serve runs the HTTP server on the listeners of listeners.toml, --addr and --h2c replace them with a single listener.
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", conf.Get().Server.ListenAddr, "listen address")
+	h2c := flags.Bool("h2c", false, "serve http2 without TLS")
+	flags.Parse(args)
 
-		http2 h2c 服务
-		h2caddrRunner := app.CreateH2CRunner(conf.Get().App.Other["listen_addr"].(string))
-	*/
+	if e := conf.Get().Validate(); e != nil {
+		freedom.Logger().Fatal(e.Error())
+	}
+	listeners := conf.Get().Listeners
+	flags.Visit(func(*flag.Flag) {
+		listeners = nil
+	})
+	if len(listeners) == 0 {
+		mode := "http"
+		if *h2c {
+			mode = "h2c"
+		}
+		listeners = []*conf.ListenerConf{{Name: "default", Addr: *addr, Mode: mode}}
+	}
+
+	installMasking(conf.Get())
+	installSlowQuery(conf.Get())
+	installTracing(conf.Get())
+	if dir := conf.Get().Server.ExportDir; dir != "" {
+		export.Dir = dir //异步导出文件目录
+	}
+	app := freedom.NewApplication()
+	installStorage(app)
 	installMiddleware(app)
-	addrRunner := app.CreateRunner(conf.Get().App.Other["listen_addr"].(string))
+	if handler := redirectHTTPS(listeners); handler != nil {
+		app.InstallMiddleware(handler)
+	}
+	installReload(app)
+	runner, e := listenerRunner(app, listeners)
+	if e != nil {
+		freedom.Logger().Fatal(e.Error())
+	}
 	//app.InstallParty("/github.com/8treenet/dump")
 	liveness(app)
-	app.Run(addrRunner, *conf.Get().App)
+	adminServer := installAdmin()
+	app.Run(runner, *conf.Get().App)
+	tracing.Shutdown() //导出剩余的span
+	repository.StopReplicas()
+	if adminServer != nil {
+		adminServer.Close()
+	}
+}
+
+// installMasking applies the masking salt and overrides of masking.toml.
+func installMasking(cfg *conf.Configuration) {
+	if e := masking.Configure(cfg.Masking.Salt, cfg.Masking.Overrides); e != nil {
+		freedom.Logger().Fatal(e.Error())
+	}
+}
+
+// installSlowQuery applies the slow query threshold and SQL comments of app.toml.
+func installSlowQuery(cfg *conf.Configuration) {
+	repository.SetSlowQuery(time.Duration(cfg.Server.SlowQueryMS)*time.Millisecond, cfg.Server.SQLComment)
+}
+
+// installTracing installs the span exporter of app.toml.
+func installTracing(cfg *conf.Configuration) {
+	var exporter sdktrace.SpanExporter
+	var e error
+	switch cfg.Server.TraceExporter {
+	case tracing.ExporterStdout:
+		exporter, e = tracing.NewStdoutExporter(os.Stdout)
+	case tracing.ExporterOTLP:
+		exporter, e = tracing.NewOTLPExporter(cfg.Server.TraceEndpoint)
+	}
+	if e != nil {
+		freedom.Logger().Fatal(e.Error())
+	}
+	tracing.Install(exporter, cfg.Server.ServiceName)
 }
 
 func installMiddleware(app freedom.Application) {
 	//Recover中间件
 	app.InstallMiddleware(middleware.NewRecover())
+	//链路追踪中间件, 每个请求一个span, 支持W3C traceparent
+	app.InstallMiddleware(tracing.NewMiddleware())
+	//接口指标中间件, 按路由模板统计请求数、延迟与大小
+	app.InstallMiddleware(metrics.NewMiddleware())
 	//Trace链路中间件
 	app.InstallMiddleware(middleware.NewTrace("x-request-id"))
 	//日志中间件，每个请求一个logger
 	app.InstallMiddleware(middleware.NewRequestLogger("x-request-id"))
+	//导入导出等管理接口需要携带admin_token, 未配置时拒绝
+	app.InstallMiddleware(admin.NewGuard("/admin/", func() string {
+		return conf.Get().Server.AdminToken
+	}))
 	//logRow中间件，每一行日志都会触发回调。如果返回true，将停止中间件遍历回调。
 	app.Logger().Handle(middleware.DefaultLogRowHandle)
 	//HttpClient 普罗米修斯中间件，监控下游的API请求。
-	requests.InstallPrometheus(conf.Get().App.Other["service_name"].(string), freedom.Prometheus())
+	requests.InstallPrometheus(conf.Get().Server.ServiceName, freedom.Prometheus())
+	//HttpClient 链路追踪中间件，下游请求的span并透传traceparent。
+	requests.InstallMiddleware(tracing.NewRequestsMiddleware())
 	//总线中间件，处理上下游透传的Header
 	app.InstallBusMiddleware(middleware.NewBusFilter())
 }
 
+// installStorage installs the database and Redis when they are configured and enabled.
+func installStorage(app freedom.Application) {
+	mode := func(installed bool) string {
+		if installed {
+			return "enabled"
+		}
+		return "disabled"
+	}
+	cfg := conf.Get()
+	app.Logger().Infof("storage mode, database: %s, redis: %s", mode(cfg.DB.Installed()), mode(cfg.Redis.Installed()))
+	if cfg.DB.Installed() {
+		installDatabase(app) //安装数据库
+	}
+	if cfg.Redis.Installed() {
+		installRedis(app) //安装redis
+	}
+}
+
+const (
+	connectAttempts   = 6
+	connectMaxBackoff = 16 * time.Second
+)
+
+// connectWithRetry retries connect with exponential backoff and exits once all attempts failed.
+func connectWithRetry(name string, connect func() error) {
+	backoff := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		e := connect()
+		if e == nil {
+			return
+		}
+		if attempt == connectAttempts {
+			freedom.Logger().Fatalf("connect %s failed after %d attempts: %v", name, attempt, e)
+		}
+		freedom.Logger().Warnf("connect %s failed, attempt %d, retry in %s: %v", name, attempt, backoff, e)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > connectMaxBackoff {
+			backoff = connectMaxBackoff
+		}
+	}
+}
+
+// openDatabase connects to addr with the driver and pool settings of db.toml.
+func openDatabase(name, addr string) (db *gorm.DB) {
+	conf := conf.Get().DB
+	connectWithRetry(name, func() (e error) {
+		db, e = gorm.Open(conf.Driver, addr)
+		return
+	})
+	db.DB().SetMaxIdleConns(conf.MaxIdleConns)
+	db.DB().SetMaxOpenConns(conf.MaxOpenConns)
+	db.DB().SetConnMaxLifetime(time.Duration(conf.ConnMaxLifeTime) * time.Second)
+	repository.InstallSlowLog(db)
+	repository.InstallTracing(db)
+	return
+}
+
 func installDatabase(app freedom.Application) {
 	app.InstallDB(func() interface{} {
 		conf := conf.Get().DB
-		db, e := gorm.Open("mysql", conf.Addr)
-		if e != nil {
-			freedom.Logger().Fatal(e.Error())
+		gormDB = openDatabase("database", conf.Addr)
+		replicas := []*gorm.DB{}
+		for index, addr := range conf.Replicas {
+			replicas = append(replicas, openDatabase(fmt.Sprintf("database replica %d", index), addr))
 		}
-
-		db.DB().SetMaxIdleConns(conf.MaxIdleConns)
-		db.DB().SetMaxOpenConns(conf.MaxOpenConns)
-		db.DB().SetConnMaxLifetime(time.Duration(conf.ConnMaxLifeTime) * time.Second)
-		return db
+		replicaDBs = replicas
+		repository.InstallReplicas(replicas, 10*time.Second)
+		return gormDB
 	})
 }
 
 func installRedis(app freedom.Application) {
 	app.InstallRedis(func() (client redis.Cmdable) {
-		cfg := conf.Get().Redis
-		opt := &redis.Options{
-			Addr:               cfg.Addr,
-			Password:           cfg.Password,
-			DB:                 cfg.DB,
-			MaxRetries:         cfg.MaxRetries,
-			PoolSize:           cfg.PoolSize,
-			ReadTimeout:        time.Duration(cfg.ReadTimeout) * time.Second,
-			WriteTimeout:       time.Duration(cfg.WriteTimeout) * time.Second,
-			IdleTimeout:        time.Duration(cfg.IdleTimeout) * time.Second,
-			IdleCheckFrequency: time.Duration(cfg.IdleCheckFrequency) * time.Second,
-			MaxConnAge:         time.Duration(cfg.MaxConnAge) * time.Second,
-			PoolTimeout:        time.Duration(cfg.PoolTimeout) * time.Second,
-		}
-		redisClient := redis.NewClient(opt)
-		if e := redisClient.Ping().Err(); e != nil {
-			freedom.Logger().Fatal(e.Error())
-		}
-		client = redisClient
+		redisClient = newSwappableRedis(conf.Get().Redis)
+		connectWithRetry("redis", func() error {
+			return redisClient.Ping().Err()
+		})
+		client = redisClient.Client
 		return
 	})
 }
 
+// installReload applies reloaded configuration to the logger, the DB pool and the Redis client.
+func installReload(app freedom.Application) {
+	conf.Subscribe(func(old, next *conf.Configuration) {
+		if next.Server.LoggerLevel != old.Server.LoggerLevel {
+			app.Logger().SetLevel(next.Server.LoggerLevel)
+		}
+		if !reflect.DeepEqual(next.Masking, old.Masking) {
+			installMasking(next)
+		}
+		installSlowQuery(next)
+		if next.Server.TraceExporter != old.Server.TraceExporter || next.Server.TraceEndpoint != old.Server.TraceEndpoint {
+			installTracing(next)
+		}
+		if next.Server.AdminEnabled != old.Server.AdminEnabled || next.Server.AdminListenAddr != old.Server.AdminListenAddr || next.Server.AdminToken != old.Server.AdminToken {
+			app.Logger().Warn("admin listener changes take effect after a restart")
+		}
+		if !reflect.DeepEqual(next.Listeners, old.Listeners) {
+			app.Logger().Warn("listeners changes take effect after a restart, tls certificates are reloaded automatically")
+		}
+
+		dbs := replicaDBs
+		if gormDB != nil {
+			dbs = append([]*gorm.DB{gormDB}, dbs...)
+		}
+		for _, db := range dbs {
+			db.DB().SetMaxIdleConns(next.DB.MaxIdleConns)
+			db.DB().SetMaxOpenConns(next.DB.MaxOpenConns)
+			db.DB().SetConnMaxLifetime(time.Duration(next.DB.ConnMaxLifeTime) * time.Second)
+		}
+
+		if redisClient != nil && !reflect.DeepEqual(next.Redis, old.Redis) {
+			//新建客户端替换, 连接池参数也可以生效
+			if e := redisClient.Swap(next.Redis); e != nil {
+				app.Logger().Errorf("redis reload failed, the previous client is kept: %v", e)
+			}
+		}
+	})
+	conf.Watch(2 * time.Second)
+}
+
+// installAdmin serves pprof and the runtime, configuration and pool stats on admin_listen_addr when admin_enabled.
+func installAdmin() *http.Server {
+	cfg := conf.Get().Server
+	if !cfg.AdminEnabled {
+		return nil
+	}
+	admin.Config = func(w io.Writer) {
+		conf.Get().Print(w)
+	}
+	//连接在InstallDB和InstallRedis的回调中建立, 读取时再判断
+	admin.Register("database", func() interface{} {
+		if gormDB == nil {
+			return nil
+		}
+		return gormDB.DB().Stats()
+	})
+	admin.Register("database_replicas", func() interface{} {
+		stats := []interface{}{}
+		for _, db := range replicaDBs {
+			stats = append(stats, db.DB().Stats())
+		}
+		return stats
+	})
+	admin.Register("redis", func() interface{} {
+		if redisClient == nil {
+			return nil
+		}
+		return redisClient.PoolStats()
+	})
+
+	server, e := admin.Serve(cfg.AdminListenAddr, cfg.AdminToken)
+	if e != nil {
+		freedom.Logger().Fatalf("admin listener: %v", e)
+	}
+	freedom.Logger().Infof("admin listener on %s", cfg.AdminListenAddr)
+	return server
+}
+
 func liveness(app freedom.Application) {
 	app.Iris().Get("/ping", func(ctx freedom.Context) {
 		ctx.WriteString("pong")
 	})
+	app.Iris().Get("/healthz", health.Liveness)
+	app.Iris().Get("/readyz", health.Readiness)
+
+	if conf.Get().DB.Installed() {
+		health.Register("database", func(ctx context.Context) error {
+			if gormDB == nil {
+				return fmt.Errorf("not connected")
+			}
+			return gormDB.DB().PingContext(ctx)
+		})
+		health.Register("migrations", func(ctx context.Context) error {
+			if gormDB == nil {
+				return fmt.Errorf("not connected")
+			}
+			pending, e := migration.New(gormDB, migrationsDir()).Pending()
+			if e != nil {
+				return e
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("%d pending migrations, the first is %d_%s", len(pending), pending[0].Version, pending[0].Name)
+			}
+			return nil
+		})
+	}
+	if conf.Get().Redis.Installed() {
+		health.Register("redis", func(ctx context.Context) error {
+			if redisClient == nil {
+				return fmt.Errorf("not connected")
+			}
+			return redisClient.WithContext(ctx).Ping().Err()
+		})
+	}
+
+	//中断回调按注册顺序执行, 在 app.Run 注册的优雅关闭之前先让 readyz 失败并等待负载均衡摘除实例。
+	iris.RegisterOnInterrupt(func() {
+		health.Drain(time.Duration(conf.Get().Server.DrainSecond) * time.Second)
+	})
 }
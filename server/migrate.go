@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/8treenet/dump/infra/migration"
+	"github.com/8treenet/dump/server/conf"
+)
+
+const migrateUsage = `usage: server migrate <command> [flags]
+
+commands:
+  up      apply pending migrations
+  down    revert applied migrations, the latest first
+  status  list migrations and whether they are applied
+  create  create an empty pair of SQL migrations, e.g. migrate create add_goods_index
+`
+
+// migrate runs the migrate subcommand.
+func migrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	dir := flags.String("dir", migrationsDir(), "SQL migrations directory")
+	dryRun := flags.Bool("dry-run", false, "print the migrations without applying them")
+	steps := flags.Int("n", 0, "number of migrations, up defaults to all and down to 1")
+	flags.Parse(args[1:])
+
+	if args[0] == "create" {
+		if flags.NArg() == 0 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		up, down, e := migration.Create(*dir, flags.Arg(0))
+		exitOnError(e)
+		fmt.Println("created", up)
+		fmt.Println("created", down)
+		return
+	}
+
+	if !conf.Get().DB.Installed() {
+		exitOnError(fmt.Errorf("database is not configured, see db.toml"))
+	}
+	db := openDatabase("database", conf.Get().DB.Addr)
+	defer db.Close()
+	migrator := migration.New(db, *dir)
+	migrator.DryRun = *dryRun
+
+	switch args[0] {
+	case "up":
+		exitOnError(migrator.Up(*steps))
+	case "down":
+		if *steps == 0 {
+			*steps = 1
+		}
+		exitOnError(migrator.Down(*steps))
+	case "status":
+		status, e := migrator.Status()
+		exitOnError(e)
+		for _, item := range status {
+			state := "pending"
+			if item.Applied {
+				state = "applied " + item.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if item.Progress > 0 {
+				state = fmt.Sprintf("pending, interrupted after %d statements", item.Progress)
+			} else if item.Progress < 0 {
+				state = fmt.Sprintf("applied, down interrupted after %d statements", -item.Progress)
+			}
+			fmt.Printf("%-16d %-40s %s\n", item.Migration.Version, item.Migration.Name, state)
+		}
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
+
+func exitOnError(e error) {
+	if e != nil {
+		fmt.Fprintln(os.Stderr, "error:", e)
+		os.Exit(1)
+	}
+}
+
+// migrationsDir returns the SQL migrations directory.
+func migrationsDir() string {
+	return siblingDir("migrations")
+}
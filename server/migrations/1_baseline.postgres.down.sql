@@ -0,0 +1,15 @@
+-- baseline down
+
+DROP TABLE IF EXISTS "test_emails";
+DROP TABLE IF EXISTS "test_users";
+DROP TABLE IF EXISTS "dump";
+DROP TABLE IF EXISTS "delivery";
+DROP TABLE IF EXISTS "order_log";
+DROP TABLE IF EXISTS "order_detail";
+DROP TABLE IF EXISTS "order";
+DROP TABLE IF EXISTS "cart";
+DROP TABLE IF EXISTS "albums";
+DROP TABLE IF EXISTS "product";
+DROP TABLE IF EXISTS "goods";
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS "admin";
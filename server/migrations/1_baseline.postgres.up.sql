@@ -0,0 +1,133 @@
+-- baseline up: the fshop schema, existing tables are left as they are
+
+CREATE TABLE IF NOT EXISTS "admin" (
+  "id" serial PRIMARY KEY,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "admin"."name" IS '管理员名称';
+
+CREATE TABLE IF NOT EXISTS "user" (
+  "id" serial PRIMARY KEY,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "money" integer NOT NULL DEFAULT 0,
+  "password" varchar(255) NOT NULL DEFAULT '',
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "user"."id" IS '用户id';
+COMMENT ON COLUMN "user"."name" IS '用户名称';
+COMMENT ON COLUMN "user"."money" IS '金钱';
+COMMENT ON COLUMN "user"."password" IS '密码';
+
+CREATE TABLE IF NOT EXISTS "goods" (
+  "id" serial PRIMARY KEY,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "price" integer NOT NULL DEFAULT 0,
+  "stock" integer NOT NULL DEFAULT 0,
+  "tag" varchar(255) NOT NULL DEFAULT '',
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "goods"."name" IS '商品名称';
+COMMENT ON COLUMN "goods"."price" IS '价格';
+COMMENT ON COLUMN "goods"."stock" IS '库存';
+COMMENT ON COLUMN "goods"."tag" IS '标签';
+
+CREATE TABLE IF NOT EXISTS "product" (
+  "id" serial PRIMARY KEY,
+  "price" integer NOT NULL DEFAULT 0,
+  "desc" varchar(255) NOT NULL DEFAULT '',
+  "test" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "albums" (
+  "AlbumId" serial PRIMARY KEY,
+  "Title" varchar(255) NOT NULL DEFAULT '',
+  "ArtistId" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "cart" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "goods_id" integer NOT NULL DEFAULT 0,
+  "num" integer NOT NULL DEFAULT 0,
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "cart"."user_id" IS '用户ID';
+COMMENT ON COLUMN "cart"."goods_id" IS '商品id';
+COMMENT ON COLUMN "cart"."num" IS '数量';
+
+CREATE TABLE IF NOT EXISTS "order" (
+  "id" serial PRIMARY KEY,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "user_id" integer NOT NULL DEFAULT 0,
+  "total_price" integer NOT NULL DEFAULT 0,
+  "status" varchar(255) NOT NULL DEFAULT '',
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "order"."user_id" IS '用户id';
+COMMENT ON COLUMN "order"."total_price" IS '总价';
+COMMENT ON COLUMN "order"."status" IS '支付,未支付，发货，完成';
+
+CREATE TABLE IF NOT EXISTS "order_detail" (
+  "id" serial PRIMARY KEY,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "goods_id" integer NOT NULL DEFAULT 0,
+  "num" integer NOT NULL DEFAULT 0,
+  "goods_name" varchar(255) NOT NULL DEFAULT '',
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "order_detail"."order_no" IS '订单id';
+COMMENT ON COLUMN "order_detail"."goods_id" IS '商品id';
+COMMENT ON COLUMN "order_detail"."num" IS '数量';
+COMMENT ON COLUMN "order_detail"."goods_name" IS '商品名称';
+
+CREATE TABLE IF NOT EXISTS "order_log" (
+  "id" serial PRIMARY KEY,
+  "order_id" integer NOT NULL DEFAULT 0,
+  "desc" varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS "delivery" (
+  "id" serial PRIMARY KEY,
+  "admin_id" integer NOT NULL DEFAULT 0,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "tracking_number" varchar(255) NOT NULL DEFAULT '',
+  "created" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+COMMENT ON COLUMN "delivery"."admin_id" IS '管理员id';
+COMMENT ON COLUMN "delivery"."tracking_number" IS '快递单号';
+
+CREATE TABLE IF NOT EXISTS "dump" (
+  "id" serial PRIMARY KEY,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "dump" text NOT NULL
+);
+COMMENT ON COLUMN "dump"."name" IS '管理员名称';
+
+CREATE TABLE IF NOT EXISTS "test_users" (
+  "id" serial PRIMARY KEY,
+  "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" timestamp with time zone NULL,
+  "user_name" varchar(255) NOT NULL DEFAULT '',
+  "password" varchar(255) NOT NULL DEFAULT '',
+  "age" integer NOT NULL DEFAULT 0,
+  "status" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "test_emails" (
+  "id" serial PRIMARY KEY,
+  "created_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" timestamp with time zone NULL,
+  "type_id" integer NOT NULL DEFAULT 0,
+  "subscribed" integer NOT NULL DEFAULT 0,
+  "test_user_id" integer NOT NULL DEFAULT 0
+);
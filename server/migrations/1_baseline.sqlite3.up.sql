@@ -0,0 +1,111 @@
+-- baseline up: the fshop schema, existing tables are left as they are
+
+CREATE TABLE IF NOT EXISTS "admin" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "user" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "money" integer NOT NULL DEFAULT 0,
+  "password" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "goods" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "price" integer NOT NULL DEFAULT 0,
+  "stock" integer NOT NULL DEFAULT 0,
+  "tag" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "product" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "price" integer NOT NULL DEFAULT 0,
+  "desc" varchar(255) NOT NULL DEFAULT '',
+  "test" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "albums" (
+  "AlbumId" integer PRIMARY KEY AUTOINCREMENT,
+  "Title" varchar(255) NOT NULL DEFAULT '',
+  "ArtistId" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "cart" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "user_id" integer NOT NULL DEFAULT 0,
+  "goods_id" integer NOT NULL DEFAULT 0,
+  "num" integer NOT NULL DEFAULT 0,
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "order" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "user_id" integer NOT NULL DEFAULT 0,
+  "total_price" integer NOT NULL DEFAULT 0,
+  "status" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "order_detail" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "goods_id" integer NOT NULL DEFAULT 0,
+  "num" integer NOT NULL DEFAULT 0,
+  "goods_name" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "order_log" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_id" integer NOT NULL DEFAULT 0,
+  "desc" varchar(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS "delivery" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "admin_id" integer NOT NULL DEFAULT 0,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "tracking_number" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "dump" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "dump" text NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "test_users" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL,
+  "user_name" varchar(255) NOT NULL DEFAULT '',
+  "password" varchar(255) NOT NULL DEFAULT '',
+  "age" integer NOT NULL DEFAULT 0,
+  "status" integer NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS "test_emails" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "created_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deleted_at" datetime NULL,
+  "type_id" integer NOT NULL DEFAULT 0,
+  "subscribed" integer NOT NULL DEFAULT 0,
+  "test_user_id" integer NOT NULL DEFAULT 0
+);
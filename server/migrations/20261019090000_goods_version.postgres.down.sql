@@ -0,0 +1,3 @@
+-- goods_version down
+
+ALTER TABLE "goods" DROP COLUMN "version";
@@ -0,0 +1,4 @@
+-- goods_version up: the version of the conditional saves, incremented by every save
+
+ALTER TABLE "goods" ADD COLUMN "version" integer NOT NULL DEFAULT 0;
+COMMENT ON COLUMN "goods"."version" IS '版本';
@@ -0,0 +1,15 @@
+-- goods_version down: sqlite before 3.35 can't drop a column, the table is rebuilt without it
+
+CREATE TABLE "goods_down" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "price" integer NOT NULL DEFAULT 0,
+  "stock" integer NOT NULL DEFAULT 0,
+  "tag" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO "goods_down" ("id", "name", "price", "stock", "tag", "created", "updated")
+  SELECT "id", "name", "price", "stock", "tag", "created", "updated" FROM "goods";
+DROP TABLE "goods";
+ALTER TABLE "goods_down" RENAME TO "goods";
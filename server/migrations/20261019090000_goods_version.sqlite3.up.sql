@@ -0,0 +1,3 @@
+-- goods_version up: the version of the conditional saves, incremented by every save
+
+ALTER TABLE "goods" ADD COLUMN "version" integer NOT NULL DEFAULT 0;
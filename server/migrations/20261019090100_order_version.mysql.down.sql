@@ -0,0 +1,3 @@
+-- order_version down
+
+ALTER TABLE `order` DROP COLUMN `version`;
@@ -0,0 +1,3 @@
+-- order_version up: the version of the conditional saves, incremented by every save
+
+ALTER TABLE `order` ADD COLUMN `version` int(11) NOT NULL DEFAULT 0 COMMENT '版本';
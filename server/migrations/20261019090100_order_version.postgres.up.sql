@@ -0,0 +1,4 @@
+-- order_version up: the version of the conditional saves, incremented by every save
+
+ALTER TABLE "order" ADD COLUMN "version" integer NOT NULL DEFAULT 0;
+COMMENT ON COLUMN "order"."version" IS '版本';
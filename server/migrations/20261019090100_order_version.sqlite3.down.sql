@@ -0,0 +1,15 @@
+-- order_version down: sqlite before 3.35 can't drop a column, the table is rebuilt without it
+
+CREATE TABLE "order_down" (
+  "id" integer PRIMARY KEY AUTOINCREMENT,
+  "order_no" varchar(255) NOT NULL DEFAULT '',
+  "user_id" integer NOT NULL DEFAULT 0,
+  "total_price" integer NOT NULL DEFAULT 0,
+  "status" varchar(255) NOT NULL DEFAULT '',
+  "created" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated" datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO "order_down" ("id", "order_no", "user_id", "total_price", "status", "created", "updated")
+  SELECT "id", "order_no", "user_id", "total_price", "status", "created", "updated" FROM "order";
+DROP TABLE "order";
+ALTER TABLE "order_down" RENAME TO "order";
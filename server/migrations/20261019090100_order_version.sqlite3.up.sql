@@ -0,0 +1,3 @@
+-- order_version up: the version of the conditional saves, incremented by every save
+
+ALTER TABLE "order" ADD COLUMN "version" integer NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+// Package migrations contains the migrations of the fshop schema. The SQL migrations are the files of this
+// directory, see 'server migrate create', the Go migrations are registered by the init functions of this package.
+// 1_baseline creates the po tables, it has a file per dialect since their DDL differs.
+package migrations
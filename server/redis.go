@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/8treenet/dump/server/conf"
+	"github.com/go-redis/redis"
+)
+
+// redisCloseDelay is how long a replaced client keeps serving the commands already sent to it.
+const redisCloseDelay = 30 * time.Second
+
+// swappableRedis is the client installed in freedom. Its commands and pipelines are forwarded to the
+// current client, which installReload replaces when redis.toml changes, e.g. for a new pool_size.
+// The Options of the embedded client are never mutated, a change always builds a new client.
+// Subscribe, Watch and Conn don't go through the forwarding, they keep the settings of the start.
+type swappableRedis struct {
+	*redis.Client
+	mu      sync.RWMutex
+	current *redis.Client
+}
+
+func newSwappableRedis(cfg *conf.RedisConf) *swappableRedis {
+	result := &swappableRedis{Client: redis.NewClient(redisOptions(cfg)), current: newRedisClient(cfg)}
+	result.WrapProcess(func(func(redis.Cmder) error) func(redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			return result.client().Process(cmd)
+		}
+	})
+	//WrapProcessPipeline依次包装Pipeline和TxPipeline的执行函数
+	tx := false
+	result.WrapProcessPipeline(func(func([]redis.Cmder) error) func([]redis.Cmder) error {
+		forward := result.forwardPipeline(tx)
+		tx = true
+		return forward
+	})
+	return result
+}
+
+// forwardPipeline executes the commands in a pipeline of the current client, in MULTI/EXEC when tx.
+func (r *swappableRedis) forwardPipeline(tx bool) func([]redis.Cmder) error {
+	return func(cmds []redis.Cmder) error {
+		pipe := r.client().Pipeline()
+		if tx {
+			pipe = r.client().TxPipeline()
+		}
+		for _, cmd := range cmds {
+			pipe.Process(cmd)
+		}
+		_, e := pipe.Exec()
+		return e
+	}
+}
+
+func (r *swappableRedis) client() *redis.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// Swap replaces the current client by a client of cfg once it answers a PING,
+// the old client is closed after redisCloseDelay.
+func (r *swappableRedis) Swap(cfg *conf.RedisConf) error {
+	next := newRedisClient(cfg)
+	if e := next.Ping().Err(); e != nil {
+		next.Close()
+		return e
+	}
+	r.mu.Lock()
+	old := r.current
+	r.current = next
+	r.mu.Unlock()
+	time.AfterFunc(redisCloseDelay, func() {
+		old.Close()
+	})
+	return nil
+}
+
+// PoolStats returns the pool stats of the current client.
+func (r *swappableRedis) PoolStats() *redis.PoolStats {
+	return r.client().PoolStats()
+}
+
+// Close closes the current client and the embedded one.
+func (r *swappableRedis) Close() error {
+	e := r.client().Close()
+	r.Client.Close()
+	return e
+}
+
+// newRedisClient .
+// The spans of the commands are recorded by tracing.Redis on a per-request clone of the swappableRedis,
+// before the forwarding, so the current client carries no context and no tracing.
+func newRedisClient(cfg *conf.RedisConf) *redis.Client {
+	return redis.NewClient(redisOptions(cfg))
+}
+
+func redisOptions(cfg *conf.RedisConf) *redis.Options {
+	return &redis.Options{
+		Addr:               cfg.Addr,
+		Password:           cfg.Password,
+		DB:                 cfg.DB,
+		MaxRetries:         cfg.MaxRetries,
+		PoolSize:           cfg.PoolSize,
+		ReadTimeout:        time.Duration(cfg.ReadTimeout) * time.Second,
+		WriteTimeout:       time.Duration(cfg.WriteTimeout) * time.Second,
+		IdleTimeout:        time.Duration(cfg.IdleTimeout) * time.Second,
+		IdleCheckFrequency: time.Duration(cfg.IdleCheckFrequency) * time.Second,
+		MaxConnAge:         time.Duration(cfg.MaxConnAge) * time.Second,
+		PoolTimeout:        time.Duration(cfg.PoolTimeout) * time.Second,
+	}
+}